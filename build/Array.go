@@ -0,0 +1,215 @@
+package build
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/akyoto/q/build/errors"
+	"github.com/akyoto/q/build/expression"
+	"github.com/akyoto/q/build/register"
+	"github.com/akyoto/q/build/token"
+	"github.com/akyoto/q/build/types"
+)
+
+// BoundsState handles the state of array bounds checks.
+type BoundsState struct {
+	counter int
+	list    []BoundsCheck
+}
+
+// BoundsCheck represents a runtime check of an array index.
+type BoundsCheck struct {
+	access    string
+	failLabel string
+}
+
+// ElementToRegister loads the array element of an index operation into the register of the operation.
+func (state *State) ElementToRegister(access *expression.Expression) error {
+	arrayRegister, arrayType, err := state.ArrayOperand(access.Children[0])
+
+	if err != nil {
+		return err
+	}
+
+	if _, isTemporary := arrayRegister.User().(spilledValue); isTemporary {
+		defer arrayRegister.Free()
+	}
+
+	// The address can't be calculated in the register
+	// that still holds the address of the array.
+	addressRegister := access.Register
+
+	if addressRegister == arrayRegister || addressRegister.IsFloat() {
+		addressRegister = state.FindFreeRegister()
+
+		if addressRegister == nil {
+			return errors.New(errors.ExceededMaxVariables)
+		}
+
+		addressRegister.ForceUse(access)
+		defer addressRegister.Free()
+	}
+
+	baseRegister, offset, err := state.ElementAddress(access, arrayRegister, arrayType, addressRegister)
+
+	if err != nil {
+		return err
+	}
+
+	access.Type = arrayType.Element
+	state.Load(access.Register, baseRegister, offset, arrayType.Element)
+	return nil
+}
+
+// OperandsToRegisters evaluates the operands of the operation that are not simple operands
+// and returns the temporary registers holding their values. The caller needs to free them.
+func (state *State) OperandsToRegisters(operation *expression.Expression) ([]*register.Register, error) {
+	var temporaryRegisters []*register.Register
+
+	for _, operand := range operation.Children {
+		if operand.IsLeaf() {
+			continue
+		}
+
+		operand.Register = state.FindFreeRegister()
+
+		if operand.Register == nil {
+			return temporaryRegisters, errors.New(errors.ExceededMaxVariables)
+		}
+
+		operand.Register.ForceUse(operand)
+		temporaryRegisters = append(temporaryRegisters, operand.Register)
+		typ, err := state.ExpressionToRegister(operand, operand.Register)
+
+		if err != nil {
+			return temporaryRegisters, err
+		}
+
+		operand.Type = typ
+	}
+
+	return temporaryRegisters, nil
+}
+
+// ArrayOperand returns the register and the type of the array in an index operation.
+// The address of a spilled array is loaded into a temporary register that the caller needs to free.
+func (state *State) ArrayOperand(array *expression.Expression) (*register.Register, *types.Type, error) {
+	arrayRegister := array.Register
+	arrayType := array.Type
+
+	if array.IsLeaf() {
+		if array.Token.Kind != token.Identifier {
+			return nil, nil, errors.New(errors.NotAnArray)
+		}
+
+		variableName := array.Token.Text()
+		variable := state.scopes.Get(variableName)
+
+		if variable == nil {
+			return nil, nil, errors.New(state.UnknownVariableError(variableName))
+		}
+
+		state.UseVariable(variable)
+		arrayType = variable.Type
+		variableRegister, err := state.VariableRegister(variable)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		arrayRegister = variableRegister
+	}
+
+	if arrayType == nil || !arrayType.IsArray() {
+		return nil, nil, errors.New(errors.NotAnArray)
+	}
+
+	return arrayRegister, arrayType, nil
+}
+
+// ElementAddress returns the base register and the offset needed to access the array element.
+// Constant indices are checked at compile time and only change the offset.
+// Variable indices are scaled by the element size and added to the array address in the address register.
+func (state *State) ElementAddress(access *expression.Expression, arrayRegister *register.Register, arrayType *types.Type, addressRegister *register.Register) (*register.Register, int32, error) {
+	index := access.Children[1]
+	elementSize := arrayType.Element.Size
+
+	if index.IsLeaf() && index.Token.Kind == token.Number {
+		number, err := state.ParseInt(index.Token.Text())
+
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if number < 0 || number >= int64(arrayType.Length) {
+			return nil, 0, errors.New(&errors.IndexOutOfBounds{Index: number, Length: arrayType.Length})
+		}
+
+		return arrayRegister, int32(number) * int32(elementSize), nil
+	}
+
+	indexRegister := index.Register
+	indexType := index.Type
+
+	if index.IsLeaf() {
+		if index.Token.Kind != token.Identifier {
+			return nil, 0, errors.New(errors.InvalidExpression)
+		}
+
+		variableName := index.Token.Text()
+		variable := state.scopes.Get(variableName)
+
+		if variable == nil {
+			return nil, 0, errors.New(state.UnknownVariableError(variableName))
+		}
+
+		state.UseVariable(variable)
+		state.AddOperand(variable)
+		indexRegister = variable.Register()
+		indexType = variable.Type
+
+		if variable.IsSpilled() {
+			state.LoadSpilled(variable, addressRegister)
+			indexRegister = addressRegister
+		}
+	}
+
+	if indexType != types.Int {
+		return nil, 0, errors.New(&errors.InvalidType{Name: indexType.String(), Expected: types.Int.String()})
+	}
+
+	state.CheckBounds(access, indexRegister, arrayType)
+
+	if addressRegister != indexRegister {
+		state.assembler.MoveRegisterRegister(addressRegister, indexRegister)
+	}
+
+	if elementSize > 1 {
+		state.assembler.ShiftLeftRegisterNumber(addressRegister, uint64(bits.TrailingZeros(elementSize)))
+	}
+
+	state.assembler.AddRegisterRegister(addressRegister, arrayRegister)
+	return addressRegister, 0, nil
+}
+
+// CheckBounds jumps to the failure report at the end of the function
+// if the index is not within the bounds of the array.
+// Like contracts, bounds checks are disabled in optimized builds.
+func (state *State) CheckBounds(access *expression.Expression, index *register.Register, arrayType *types.Type) {
+	if state.ignoreContracts {
+		return
+	}
+
+	state.boundsState.counter++
+	failLabel := fmt.Sprintf("bounds_%d_fail", state.boundsState.counter)
+
+	state.boundsState.list = append(state.boundsState.list, BoundsCheck{
+		access:    fmt.Sprintf("%v[%v]", access.Children[0], access.Children[1]),
+		failLabel: failLabel,
+	})
+
+	// Negative indices are treated as very large unsigned numbers,
+	// therefore a single unsigned comparison covers both bounds.
+	state.assembler.CompareRegisterNumber(index, uint64(arrayType.Length))
+	state.assembler.JumpIfAboveOrEqual(failLabel)
+}
@@ -1,13 +1,18 @@
 package build
 
 import (
+	"math"
+
 	"github.com/akyoto/q/build/errors"
+	"github.com/akyoto/q/build/operators"
+	"github.com/akyoto/q/build/register"
 	"github.com/akyoto/q/build/token"
+	"github.com/akyoto/q/build/types"
 )
 
 // Assignment handles assignment instructions.
 func (state *State) Assignment(tokens []token.Token) error {
-	operatorPos := token.Index(tokens, token.Operator, "=")
+	operatorPos := assignmentOperatorIndex(tokens)
 
 	if operatorPos == -1 {
 		return errors.New(errors.MissingAssignmentOperator)
@@ -15,6 +20,28 @@ func (state *State) Assignment(tokens []token.Token) error {
 
 	left := tokens[:operatorPos]
 
+	if token.IndexKind(left, token.Separator) != -1 {
+		if tokens[operatorPos].Text() != "=" {
+			return errors.New(errors.InvalidExpression)
+		}
+
+		return state.AssignReturnValues(tokens, operatorPos)
+	}
+
+	if tokens[operatorPos].Text() != "=" {
+		for _, t := range left {
+			if t.Kind == token.Keyword {
+				return errors.New(errors.MissingAssignmentOperator)
+			}
+		}
+
+		tokens = expandAssignment(tokens, operatorPos)
+	}
+
+	if left[0].Kind == token.Operator && left[0].Text() == "*" {
+		return state.AssignPointer(tokens, operatorPos)
+	}
+
 	if left[operatorPos-1].Kind == token.ArrayEnd {
 		return state.AssignArrayElement(tokens, operatorPos)
 	}
@@ -33,3 +60,90 @@ func (state *State) Assignment(tokens []token.Token) error {
 	_, err := state.AssignVariable(tokens, false)
 	return err
 }
+
+// StoreTokens stores the result of the token expression
+// at the memory address in the base register plus the offset.
+func (state *State) StoreTokens(base *register.Register, offset int32, typ *types.Type, value []token.Token) error {
+	if len(value) == 1 && value[0].Kind == token.Number && !typ.IsFloat() {
+		_, err := AdaptNumber(value[0], NumberType(value[0]), typ)
+
+		if err != nil {
+			return err
+		}
+
+		number, err := state.ParseInt(value[0].Text())
+
+		if err != nil {
+			return errors.New(err)
+		}
+
+		// 64-bit stores can only encode a sign-extended 32-bit number
+		if typ.Size < 8 || (number >= math.MinInt32 && number <= math.MaxInt32) {
+			state.assembler.StoreNumber(base, offset, byte(typ.Size), uint64(number))
+			return nil
+		}
+	}
+
+	state.BorrowRegisters()
+	valueRegister, valueType, err := state.EvaluateTokens(value)
+
+	if err != nil {
+		return errors.New(err)
+	}
+
+	if len(value) == 1 {
+		valueType, err = AdaptNumber(value[0], valueType, typ)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if typ != valueType {
+		return errors.New(&errors.InvalidType{Name: valueType.String(), Expected: typ.String()})
+	}
+
+	err = state.StoreValue(base, offset, typ, valueRegister)
+
+	if err != nil {
+		return err
+	}
+
+	if _, isVariable := valueRegister.User().(*Variable); !isVariable {
+		valueRegister.Free()
+	}
+
+	return state.ReturnRegisters()
+}
+
+// assignmentOperatorIndex returns the position of the assignment operator or -1 if it doesn't exist.
+func assignmentOperatorIndex(tokens []token.Token) token.Position {
+	for i, t := range tokens {
+		if t.Kind == token.Operator && operators.All[t.Text()].Kind == operators.Assignment {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// expandAssignment converts a compound assignment like `a += b` to `a = a + (b)`.
+func expandAssignment(tokens []token.Token, operatorPos token.Position) []token.Token {
+	left := tokens[:operatorPos]
+	right := tokens[operatorPos+1:]
+	operator := tokens[operatorPos]
+
+	expanded := make([]token.Token, 0, 2*len(left)+len(right)+4)
+	expanded = append(expanded, left...)
+	expanded = append(expanded, token.Token{Kind: token.Operator, Position: operator.Position, Bytes: operator.Bytes[len(operator.Bytes)-1:]})
+	expanded = append(expanded, left...)
+	expanded = append(expanded, token.Token{Kind: token.Operator, Position: operator.Position, Bytes: operator.Bytes[:len(operator.Bytes)-1]})
+
+	if len(right) == 1 {
+		return append(expanded, right...)
+	}
+
+	expanded = append(expanded, token.Token{Kind: token.GroupStart, Position: right[0].Position, Bytes: []byte{'('}})
+	expanded = append(expanded, right...)
+	return append(expanded, token.Token{Kind: token.GroupEnd, Position: right[len(right)-1].Position, Bytes: []byte{')'}})
+}
@@ -1,29 +1,63 @@
 package build
 
 import (
-	"fmt"
-
 	"github.com/akyoto/q/build/errors"
-	"github.com/akyoto/q/build/log"
+	"github.com/akyoto/q/build/expression"
 	"github.com/akyoto/q/build/token"
 )
 
 // AssignArrayElement assigns a value to an array element.
 func (state *State) AssignArrayElement(tokens []token.Token, operatorPos token.Position) error {
-	log.Info.Println("AssignArrayElement", tokens)
 	left := tokens[:operatorPos]
-	suffix := left[1:]
+	access, err := expression.FromTokens(left)
+
+	if err != nil {
+		return err
+	}
 
-	if suffix[0].Kind == token.ArrayStart && suffix[len(suffix)-1].Kind == token.ArrayEnd {
-		indexTokens := suffix[1 : len(suffix)-1]
+	defer access.Close()
+
+	if access.Token.Text() != "[" {
+		return errors.New(errors.InvalidExpression)
+	}
 
-		if indexTokens[0].Kind != token.Number {
-			return errors.New(errors.NotImplemented)
+	// Evaluate the array and the index if they are not simple operands
+	temporaryRegisters, err := state.OperandsToRegisters(access)
+
+	defer func() {
+		for _, temporary := range temporaryRegisters {
+			temporary.Free()
 		}
+	}()
+
+	if err != nil {
+		return err
+	}
+
+	arrayRegister, arrayType, err := state.ArrayOperand(access.Children[0])
+
+	if err != nil {
+		return err
+	}
+
+	if _, isTemporary := arrayRegister.User().(spilledValue); isTemporary {
+		temporaryRegisters = append(temporaryRegisters, arrayRegister)
+	}
+
+	addressRegister := state.FindFreeRegister()
+
+	if addressRegister == nil {
+		return errors.New(errors.ExceededMaxVariables)
+	}
+
+	addressRegister.ForceUse(access)
+	temporaryRegisters = append(temporaryRegisters, addressRegister)
+	baseRegister, offset, err := state.ElementAddress(access, arrayRegister, arrayType, addressRegister)
 
-		fmt.Println(indexTokens)
-		return errors.New(errors.NotImplemented)
+	if err != nil {
+		return err
 	}
 
-	return nil
+	right := tokens[operatorPos+1:]
+	return state.StoreTokens(baseRegister, offset, arrayType.Element, right)
 }
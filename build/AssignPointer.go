@@ -0,0 +1,42 @@
+package build
+
+import (
+	"github.com/akyoto/q/build/errors"
+	"github.com/akyoto/q/build/token"
+)
+
+// AssignPointer stores a value at the memory address of a pointer: `*p = value`.
+func (state *State) AssignPointer(tokens []token.Token, operatorPos token.Position) error {
+	pointer := tokens[1:operatorPos]
+
+	if len(pointer) == 0 {
+		return errors.New(errors.InvalidExpression)
+	}
+
+	pointerRegister, pointerType, err := state.EvaluateTokens(pointer)
+
+	if err != nil {
+		return err
+	}
+
+	if _, isVariable := pointerRegister.User().(*Variable); !isVariable {
+		defer pointerRegister.Free()
+	}
+
+	if pointerType == nil || !pointerType.IsPointer() {
+		return errors.New(errors.NotAPointer)
+	}
+
+	element := pointerType.Element
+
+	if element.IsArray() {
+		return errors.New(errors.ArrayAssignment)
+	}
+
+	if len(element.Fields) > 0 {
+		return errors.New(errors.StructAssignment)
+	}
+
+	right := tokens[operatorPos+1:]
+	return state.StoreTokens(pointerRegister, 0, element, right)
+}
@@ -0,0 +1,181 @@
+package build
+
+import (
+	"github.com/akyoto/q/build/errors"
+	"github.com/akyoto/q/build/expression"
+	"github.com/akyoto/q/build/register"
+	"github.com/akyoto/q/build/token"
+	"github.com/akyoto/q/build/types"
+)
+
+// AssignReturnValues assigns the return values of a function call to multiple variables.
+// The variable name `_` ignores the return value at that position.
+func (state *State) AssignReturnValues(tokens []token.Token, operatorPos token.Position) error {
+	position := state.tokenCursor
+	start := position
+	left := tokens[:operatorPos]
+	right := tokens[operatorPos+1:]
+	isNewVariable := false
+	mutable := false
+
+	if left[0].Kind == token.Keyword {
+		switch left[0].Text() {
+		case "let":
+
+		case "mut":
+			mutable = true
+
+		default:
+			return errors.New(errors.InvalidExpression)
+		}
+
+		isNewVariable = true
+		left = left[1:]
+		start++
+	}
+
+	names := token.Split(left, token.Separator)
+
+	for _, name := range names {
+		if len(name) != 1 || name[0].Kind != token.Identifier {
+			return errors.New(errors.ExpectedVariable)
+		}
+	}
+
+	if len(right) == 0 {
+		return errors.New(errors.MissingAssignmentExpression)
+	}
+
+	state.tokenCursor = position + operatorPos + 1
+	call, err := expression.FromTokens(right)
+
+	if err != nil {
+		return err
+	}
+
+	defer call.Close()
+	err = state.ResolveAccessors(call)
+
+	if err != nil {
+		return err
+	}
+
+	if !call.IsFunctionCall {
+		return errors.New(errors.InvalidExpression)
+	}
+
+	_, err = state.ExpressionToRegister(call, nil)
+
+	if err != nil {
+		return err
+	}
+
+	returnTypes := []*types.Type{call.Type}
+	returnValueRegisters := state.registers.ReturnValue[:1]
+	function, _, err := state.ResolveCall(call)
+
+	if err != nil {
+		return err
+	}
+
+	if function != nil {
+		returnTypes = function.ReturnTypes
+		returnValueRegisters = function.ReturnValueRegisters(state.registers)
+	}
+
+	if len(names) != len(returnTypes) {
+		return errors.New(&errors.ResultCount{
+			FunctionName:  call.Token.Text(),
+			CountGiven:    len(names),
+			CountReturned: len(returnTypes),
+		})
+	}
+
+	for i, name := range names {
+		state.tokenCursor = start + i*2
+		variableName := name[0].Text()
+
+		if variableName == "_" {
+			continue
+		}
+
+		if isNewVariable {
+			err = state.DeclareReturnValue(variableName, mutable, returnValueRegisters[i], returnTypes[i])
+		} else {
+			err = state.AssignReturnValue(variableName, returnValueRegisters[i], returnTypes[i])
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeclareReturnValue declares a new variable that holds the return value in the given register.
+func (state *State) DeclareReturnValue(variableName string, mutable bool, source *register.Register, typ *types.Type) error {
+	variable := state.scopes.Get(variableName)
+
+	if variable != nil {
+		return errors.New(&errors.VariableAlreadyExists{Name: variable.Name})
+	}
+
+	register := state.FindFreeRegisterFor(typ)
+
+	if register == nil {
+		return errors.New(errors.ExceededMaxVariables)
+	}
+
+	variable = &Variable{
+		Name:           variableName,
+		Type:           typ,
+		Position:       state.tokenCursor,
+		LastAssign:     state.tokenCursor,
+		LastAssignUsed: false,
+		Mutable:        mutable,
+		AliveUntil:     state.identifierLifeTime[variableName],
+	}
+
+	variable.ForceSetRegister(register)
+	state.assembler.MoveRegisterRegister(register, source)
+	state.scopes.Add(variable)
+	return nil
+}
+
+// AssignReturnValue assigns the return value in the given register to an existing variable.
+func (state *State) AssignReturnValue(variableName string, source *register.Register, typ *types.Type) error {
+	variable := state.scopes.Get(variableName)
+
+	if variable == nil {
+		return errors.New(state.UnknownVariableError(variableName))
+	}
+
+	if !variable.Mutable {
+		return errors.New(&errors.ImmutableVariable{Name: variable.Name})
+	}
+
+	if typ != variable.Type {
+		return errors.New(&errors.InvalidType{Name: typ.String(), Expected: variable.Type.String()})
+	}
+
+	if !variable.LastAssignUsed {
+		state.tokenCursor = variable.LastAssign
+		return errors.New(&errors.IneffectiveAssignment{Name: variable.Name})
+	}
+
+	if variable.IsSpilled() {
+		state.StoreSpilled(variable, source)
+	} else {
+		state.assembler.MoveRegisterRegister(variable.Register(), source)
+	}
+
+	variable.LastAssign = state.tokenCursor
+
+	// Currently we can't prove that the value hasn't been used inside a loop.
+	if !state.InLoop() {
+		variable.LastAssignUsed = false
+	}
+
+	return nil
+}
@@ -3,55 +3,80 @@ package build
 import (
 	"github.com/akyoto/q/build/errors"
 	"github.com/akyoto/q/build/token"
+	"github.com/akyoto/q/build/types"
 )
 
 // AssignStructField assigns a value to a struct field.
+// Fields of nested structs like `line.start.x` are stored at the sum of the field offsets.
 func (state *State) AssignStructField(tokens token.List, operatorPos token.Position) error {
 	left := tokens[:operatorPos]
 	variableName := left[0].Text()
-	fieldName := left[2].Text()
 	variable := state.scopes.Get(variableName)
 
 	if variable == nil {
 		return errors.New(state.UnknownVariableError(variableName))
 	}
 
-	field := variable.Type.FieldByName(fieldName)
+	if variable.Type == types.Text {
+		return errors.New(errors.TextAssignment)
+	}
+
+	// Struct parameters refer to the memory of the caller,
+	// therefore their fields can only be modified if the parameter is mutable.
+	if variable.IsParameter && !variable.Mutable {
+		if state.function.IsMethod && variable.Name == "self" {
+			return errors.New(errors.ImmutableReceiver)
+		}
 
-	if field == nil {
-		return errors.New(UnknownFieldError(fieldName, variable.Type))
+		return errors.New(&errors.ImmutableVariable{Name: variable.Name})
 	}
 
-	right := tokens[operatorPos+1:]
+	// Modifications of parameters are visible to the caller.
+	if variable.IsParameter {
+		variable.Used = true
+		variable.LastAssignUsed = true
+	}
+
+	variable.Modified = true
 
-	if len(right) == 1 && right[0].Kind == token.Number {
-		number, err := state.ParseInt(right[0].Text())
+	var field *types.Field
+	structType := variable.Type
+	offset := uint(0)
 
-		if err != nil {
-			return errors.New(err)
+	for i := 1; i < len(left); i += 2 {
+		if i+1 >= len(left) || left[i].Text() != "." || left[i+1].Kind != token.Identifier {
+			return errors.New(errors.InvalidExpression)
 		}
 
-		state.assembler.StoreNumber(variable.Register(), byte(field.Offset), byte(field.Type.Size), uint64(number))
-		return nil
+		fieldName := left[i+1].Text()
+		field = structType.FieldByName(fieldName)
+
+		if field == nil {
+			return errors.New(UnknownFieldError(fieldName, structType))
+		}
+
+		offset += field.Offset
+		structType = field.Type
 	}
 
-	rightRegister, rightType, err := state.EvaluateTokens(right)
+	if field.Type.IsArray() {
+		return errors.New(errors.ArrayAssignment)
+	}
 
-	if err != nil {
-		return errors.New(err)
+	if len(field.Type.Fields) > 0 {
+		return errors.New(errors.StructAssignment)
 	}
 
-	err = rightRegister.Use(right)
+	structRegister, err := state.VariableRegister(variable)
 
 	if err != nil {
-		return errors.New(err)
+		return err
 	}
 
-	if field.Type != rightType {
-		return errors.New(&errors.InvalidType{Name: rightType.String(), Expected: field.Type.String()})
+	if structRegister != variable.Register() {
+		defer structRegister.Free()
 	}
 
-	state.assembler.StoreRegister(variable.Register(), byte(field.Offset), byte(field.Type.Size), rightRegister)
-	rightRegister.Free()
-	return nil
+	right := tokens[operatorPos+1:]
+	return state.StoreTokens(structRegister, int32(offset), field.Type, right)
 }
@@ -3,6 +3,7 @@ package build
 import (
 	"github.com/akyoto/q/build/errors"
 	"github.com/akyoto/q/build/token"
+	"github.com/akyoto/q/build/types"
 )
 
 // AssignVariable handles assignment instructions and also returns the referenced variable.
@@ -46,7 +47,8 @@ func (state *State) AssignVariable(tokens []token.Token, isNewVariable bool) (*V
 			return variable, errors.New(&errors.VariableAlreadyExists{Name: variable.Name})
 		}
 
-		register := state.registers.General.FindFree()
+		// The type of the value decides which kind of register the variable needs
+		register := state.FindFreeRegisterFor(state.TokensType(tokens[cursor+2:]))
 
 		if register == nil {
 			return nil, errors.ExceededMaxVariables
@@ -94,12 +96,20 @@ func (state *State) AssignVariable(tokens []token.Token, isNewVariable bool) (*V
 	}
 
 	// Move result of expression to register
-	typ, err := state.TokensToRegister(value, variable.Register())
+	typ, err := state.AssignValue(variable, value)
 
 	if err != nil {
 		return variable, err
 	}
 
+	if len(value) == 1 {
+		typ, err = AdaptNumber(value[0], typ, variable.Type)
+
+		if err != nil {
+			return variable, err
+		}
+	}
+
 	if isNewVariable {
 		variable.Type = typ
 	} else if typ != variable.Type {
@@ -124,3 +134,30 @@ func (state *State) AssignVariable(tokens []token.Token, isNewVariable bool) (*V
 	state.tokenCursor += len(value)
 	return variable, nil
 }
+
+// AssignValue moves the result of the expression into the variable.
+// Spilled variables receive the result via a temporary register.
+func (state *State) AssignValue(variable *Variable, value []token.Token) (*types.Type, error) {
+	if !variable.IsSpilled() {
+		state.AddOperand(variable)
+		return state.TokensToRegister(value, variable.Register())
+	}
+
+	state.BorrowRegisters()
+	temporary := state.TemporaryRegisterFor(variable.Type)
+
+	if temporary == nil {
+		return nil, errors.New(errors.ExceededMaxVariables)
+	}
+
+	temporary.ForceUse(spilledValue{variable})
+	typ, err := state.TokensToRegister(value, temporary)
+
+	if err != nil {
+		return nil, err
+	}
+
+	state.StoreSpilled(variable, temporary)
+	temporary.Free()
+	return typ, state.ReturnRegisters()
+}
@@ -0,0 +1,209 @@
+package build
+
+import (
+	"fmt"
+
+	"github.com/akyoto/q/build/errors"
+	"github.com/akyoto/q/build/expression"
+	"github.com/akyoto/q/build/operators"
+	"github.com/akyoto/q/build/register"
+	"github.com/akyoto/q/build/token"
+	"github.com/akyoto/q/build/types"
+)
+
+// BoolValue returns the value of the `true` and `false` keywords.
+func BoolValue(keyword token.Token) (uint64, error) {
+	switch keyword.Text() {
+	case "true":
+		return 1, nil
+
+	case "false":
+		return 0, nil
+
+	default:
+		return 0, errors.New(errors.InvalidExpression)
+	}
+}
+
+// isComparison returns true if the expression is a comparison like `a < b`.
+func isComparison(expr *expression.Expression) bool {
+	return expr.Token.Kind == token.Operator && !expr.IsFunctionCall && operators.All[expr.Token.Text()].Kind == operators.Comparison
+}
+
+// isLogical returns true if the expression is a logical operation like `a && b`.
+func isLogical(expr *expression.Expression) bool {
+	return expr.Token.Kind == token.Operator && !expr.IsFunctionCall && operators.All[expr.Token.Text()].Kind == operators.Logical
+}
+
+// LogicalToRegister moves the result of a logical operation into the register.
+// The right operand is only evaluated if the left operand doesn't determine the result.
+func (state *State) LogicalToRegister(root *expression.Expression, finalRegister *register.Register) (*types.Type, error) {
+	// The right operand is only evaluated at runtime if it is needed,
+	// therefore the variables can't be spilled here.
+	state.stackState.locked++
+	defer func() { state.stackState.locked-- }()
+
+	resultRegister := finalRegister
+
+	if finalRegister == nil || state.ReadsRegister(root.Children[1], finalRegister, nil) {
+		finalRegister = state.FindFreeRegister()
+
+		if finalRegister == nil {
+			return nil, errors.New(errors.ExceededMaxVariables)
+		}
+
+		finalRegister.ForceUse(root)
+		defer finalRegister.Free()
+	}
+
+	state.conditionState.counter++
+	endLabel := fmt.Sprintf("condition_%d_end", state.conditionState.counter)
+
+	for i, operand := range root.Children {
+		typ, err := state.ExpressionToRegister(operand, finalRegister)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if typ != types.Bool {
+			return nil, errors.New(&errors.InvalidType{Name: typ.String(), Expected: types.Bool.String()})
+		}
+
+		if i == len(root.Children)-1 {
+			break
+		}
+
+		state.assembler.CompareRegisterNumber(finalRegister, 0)
+
+		// "&&" is false as soon as one operand is false,
+		// "||" is true as soon as one operand is true.
+		if root.Token.Text() == "&&" {
+			state.assembler.JumpIfEqual(endLabel)
+		} else {
+			state.assembler.JumpIfNotEqual(endLabel)
+		}
+	}
+
+	state.assembler.AddLabel(endLabel)
+	root.Type = types.Bool
+
+	if resultRegister != nil && resultRegister != finalRegister {
+		state.assembler.MoveRegisterRegister(resultRegister, finalRegister)
+	}
+
+	return types.Bool, nil
+}
+
+// ComparisonToRegister compares the operands of the comparison
+// and stores the result as a boolean in the register of the comparison.
+func (state *State) ComparisonToRegister(comparison *expression.Expression) error {
+	left := comparison.Children[0]
+	right := comparison.Children[1]
+
+	if left.IsLeaf() {
+		typ := state.ExpressionType(left)
+
+		// Number literals adapt to the type of the other operand
+		if left.Token.Kind == token.Number {
+			typ = state.ExpressionType(right)
+		}
+
+		if left.Token.Kind == token.Number && left.Register.IsFloat() {
+			err := state.FloatNumberToRegister(left.Token, typ, left.Register)
+
+			if err != nil {
+				return err
+			}
+		} else {
+			numberType, err := state.TokenToRegister(left.Token, left.Register)
+
+			if err != nil {
+				return err
+			}
+
+			typ, err = AdaptNumber(left.Token, numberType, typ)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		left.Type = typ
+	}
+
+	if left.Type == nil {
+		return errors.New(&errors.CantInferType{Expression: left.String()})
+	}
+
+	var rightType *types.Type
+
+	if right.IsLeaf() {
+		temporary, typ, err := state.CompareRegisterExpression(left.Register, left.Type, []token.Token{right.Token}, "")
+
+		if err != nil {
+			return err
+		}
+
+		if temporary != nil {
+			temporary.Free()
+		}
+
+		rightType = typ
+	} else {
+		state.CompareRegisterRegister(left.Register, right.Register, left.Type)
+		rightType = right.Type
+	}
+
+	if rightType == nil {
+		return errors.New(&errors.CantInferType{Expression: right.String()})
+	}
+
+	if rightType != left.Type {
+		return errors.New(&errors.InvalidType{Name: rightType.String(), Expected: left.Type.String()})
+	}
+
+	// Floating-point comparisons set the flags like unsigned comparisons
+	unsigned := left.Type.IsUnsigned() || left.Type.IsFloat()
+	state.SetIf(comparison.Token.Text(), comparison.Register, unsigned)
+	comparison.Type = types.Bool
+	return nil
+}
+
+// Negate inverts the boolean value in the register of the `!` operation.
+func (state *State) Negate(negation *expression.Expression) error {
+	if negation.Type != types.Bool {
+		return errors.New(&errors.InvalidType{Name: negation.Type.String(), Expected: types.Bool.String()})
+	}
+
+	state.assembler.XorRegisterNumber(negation.Register, 1)
+	return nil
+}
+
+// CalculateBool performs a bitwise or logical operation on two boolean registers.
+// Logical operations inside of other expressions are not short-circuited.
+func (state *State) CalculateBool(operation string, typ *types.Type, operandType *types.Type, registerTo *register.Register, registerFrom *register.Register) error {
+	if typ != types.Bool {
+		return errors.New(&errors.InvalidType{Name: typ.String(), Expected: types.Bool.String()})
+	}
+
+	if operandType != typ {
+		return errors.New(&errors.InvalidType{Name: operandType.String(), Expected: typ.String()})
+	}
+
+	switch operation {
+	case "&&", "&":
+		state.assembler.AndRegisterRegister(registerTo, registerFrom)
+
+	case "||", "|":
+		state.assembler.OrRegisterRegister(registerTo, registerFrom)
+
+	case "^":
+		state.assembler.XorRegisterRegister(registerTo, registerFrom)
+
+	default:
+		return errors.New(&errors.UnsupportedOperator{Operator: operation, Type: typ.Name})
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package build
+
+import (
+	"github.com/akyoto/q/build/errors"
+	"github.com/akyoto/q/build/token"
+)
+
+// BreakState handles the state of break and continue compilation.
+type BreakState struct {
+	stack []BreakTarget
+}
+
+// BreakTarget represents the jump targets of the loop a break or continue refers to.
+type BreakTarget struct {
+	labelBreak    string
+	labelContinue string
+}
+
+// Break handles break statements.
+func (state *State) Break(tokens []token.Token) error {
+	if len(tokens) > 1 {
+		return errors.New(errors.InvalidExpression)
+	}
+
+	if len(state.breakState.stack) == 0 {
+		return errors.New(errors.BreakOutsideLoop)
+	}
+
+	target := state.breakState.stack[len(state.breakState.stack)-1]
+	state.assembler.Jump(target.labelBreak)
+	return nil
+}
+
+// PushBreakTarget registers the labels of a new innermost loop.
+func (state *State) PushBreakTarget(labelBreak string, labelContinue string) {
+	state.breakState.stack = append(state.breakState.stack, BreakTarget{
+		labelBreak:    labelBreak,
+		labelContinue: labelContinue,
+	})
+}
+
+// PopBreakTarget removes the labels of the innermost loop.
+func (state *State) PopBreakTarget() {
+	state.breakState.stack = state.breakState.stack[:len(state.breakState.stack)-1]
+}
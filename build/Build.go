@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/akyoto/asm"
@@ -102,8 +103,38 @@ func (build *Build) Run() error {
 	return nil
 }
 
+// ResolveTypes parses the input files and calculates the memory layout of all structs
+// without compiling any functions.
+func (build *Build) ResolveTypes() error {
+	err := build.Environment.ImportDirectory(build.Path, "")
+
+	if err != nil {
+		return err
+	}
+
+	return build.Environment.ResolveTypes()
+}
+
 // Compile compiles all the functions in the environment.
 func (build *Build) Compile() (*asm.Assembler, error) {
+	err := build.Environment.ResolveTypes()
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = build.Environment.ResolveConstants()
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = build.Environment.ResolveFunctions()
+
+	if err != nil {
+		return nil, err
+	}
+
 	_, exists := build.Environment.Functions["main"]
 
 	if !exists {
@@ -121,6 +152,12 @@ func (build *Build) Compile() (*asm.Assembler, error) {
 		return nil, nil
 	}
 
+	// The runtime allocator is only needed if a struct is allocated on the heap.
+	// It's added before the functions so that every call refers to a known label.
+	if atomic.LoadInt32(&build.Environment.Allocator.CallCount) > 0 {
+		build.merge(finalCode, build.Environment.Allocator)
+	}
+
 	for _, function := range build.Environment.Functions {
 		if function.Error != nil {
 			return nil, function.Error
@@ -138,16 +175,13 @@ func (build *Build) Compile() (*asm.Assembler, error) {
 			continue
 		}
 
-		// Merge function code into the main finalCode
-		finalCode.Merge(function.assembler.Finalize())
+		build.merge(finalCode, function)
+	}
 
-		// Show assembler code of used functions
-		if build.ShowAssembly {
-			log.Info.SetPrefix(log.Faint.Sprint(function.Name) + " ")
-			function.assembler.WriteTo(log.Info)
-			log.Info.SetPrefix("")
-			log.Info.Println()
-		}
+	err = build.Environment.UnusedConstant()
+
+	if err != nil {
+		return nil, err
 	}
 
 	for _, err := range finalCode.Verify() {
@@ -157,6 +191,19 @@ func (build *Build) Compile() (*asm.Assembler, error) {
 	return finalCode, nil
 }
 
+// merge adds the machine code of the function to the final code.
+func (build *Build) merge(finalCode *asm.Assembler, function *Function) {
+	finalCode.Merge(function.assembler.Finalize())
+
+	// Show assembler code of used functions
+	if build.ShowAssembly {
+		log.Info.SetPrefix(log.Faint.Sprint(function.Name) + " ")
+		function.assembler.WriteTo(log.Info)
+		log.Info.SetPrefix("")
+		log.Info.Println()
+	}
+}
+
 // writeToDisk writes the executable file to disk.
 func writeToDisk(main *asm.Assembler, filePath string) error {
 	binary := elf.New(main)
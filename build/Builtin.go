@@ -5,6 +5,7 @@ import "github.com/akyoto/q/build/types"
 const (
 	BuiltinSyscall = "syscall"
 	BuiltinPrint   = "print"
+	BuiltinLoad    = "load"
 	BuiltinStore   = "store"
 )
 
@@ -19,6 +20,16 @@ var BuiltinFunctions = map[string]*Function{
 		IsBuiltin:   true,
 		SideEffects: 1,
 	},
+	BuiltinLoad: {
+		Name: BuiltinLoad,
+		Parameters: []*Parameter{
+			{Name: "ptr", Type: types.Pointer},
+			{Name: "offset", Type: types.Int},
+			{Name: "byteCount", Type: types.Int},
+		},
+		ReturnTypes: []*types.Type{types.Int},
+		IsBuiltin:   true,
+	},
 	BuiltinStore: {
 		Name: BuiltinStore,
 		Parameters: []*Parameter{
@@ -2,7 +2,6 @@ package build
 
 import (
 	"fmt"
-	"strconv"
 	"sync/atomic"
 
 	"github.com/akyoto/asm/syscall"
@@ -10,6 +9,7 @@ import (
 	"github.com/akyoto/q/build/expression"
 	"github.com/akyoto/q/build/register"
 	"github.com/akyoto/q/build/token"
+	"github.com/akyoto/q/build/types"
 )
 
 // Call handles function calls.
@@ -34,32 +34,34 @@ func (state *State) Call(tokens []token.Token) error {
 func (state *State) CallExpression(expr *expression.Expression) error {
 	parameters := expr.Children
 	functionName := expr.Token.Text()
-	functionName = PolymorphName(functionName, len(parameters))
-	function := state.environment.Functions[functionName]
-	isBuiltin := false
 
-	if function == nil {
-		function = BuiltinFunctions[functionName]
-		isBuiltin = true
+	if expr.IsStructLiteral {
+		typ := state.environment.Type(functionName)
+
+		if typ == nil {
+			return errors.New(state.environment.UnknownTypeError(functionName))
+		}
+
+		return state.StructLiteral(expr, typ)
+	}
+
+	function, isBuiltin, err := state.ResolveCall(expr)
+
+	if err != nil {
+		return err
 	}
 
 	if function == nil {
-		typ := state.environment.Types[functionName]
+		typ := state.environment.Type(functionName)
 
-		if typ != nil {
-			state.assembler.MoveRegisterNumber(state.registers.Syscall[0], 9)
-			state.assembler.MoveRegisterNumber(state.registers.Syscall[1], 0)
-			state.assembler.MoveRegisterNumber(state.registers.Syscall[2], uint64(typ.Size))
-			state.assembler.MoveRegisterNumber(state.registers.Syscall[3], 3)
-			state.assembler.MoveRegisterNumber(state.registers.Syscall[4], 290)
-			state.assembler.Syscall()
-
-			if expr.Register != state.registers.ReturnValue[0] {
-				state.assembler.MoveRegisterRegister(expr.Register, state.registers.ReturnValue[0])
-			}
+		// Default types like `Int(x)` or `Float(x)` convert their parameter
+		if typ != nil && len(parameters) == 1 && types.Default[typ.Name] == typ {
+			return state.Convert(expr, typ)
+		}
 
-			expr.Type = typ
-			return nil
+		// `Point()` and `[4]Int()` create zero-initialized structs and arrays
+		if typ != nil {
+			return state.StructLiteral(expr, typ)
 		}
 
 		return errors.New(state.environment.UnknownFunctionError(functionName))
@@ -73,7 +75,7 @@ func (state *State) CallExpression(expr *expression.Expression) error {
 	// Parameter check
 	if !function.NoParameterCheck && len(parameters) != len(function.Parameters) {
 		return errors.New(&errors.ParameterCount{
-			FunctionName:  function.Name,
+			FunctionName:  functionName,
 			CountGiven:    len(parameters),
 			CountRequired: len(function.Parameters),
 		})
@@ -82,74 +84,80 @@ func (state *State) CallExpression(expr *expression.Expression) error {
 	if isBuiltin {
 		switch functionName {
 		case BuiltinPrint:
-			parameter := parameters[0]
-
-			if parameter.Token.Kind != token.Text {
-				return fmt.Errorf("'%s' requires a text parameter instead of '%s'", function.Name, parameter.Token.Text())
-			}
+			return state.Print(expr)
 
-			state.printLn(parameter.Token.Text())
-			return nil
+		case BuiltinLoad:
+			return state.LoadMemory(expr)
 
 		case BuiltinStore:
-			variableName := parameters[0].Token.Text()
-			offsetString := parameters[1].Token.Text()
-			byteCountString := parameters[2].Token.Text()
-			valueString := parameters[3].Token.Text()
-
-			variable := state.scopes.Get(variableName)
-			offset, _ := strconv.Atoi(offsetString)
-			byteCount, _ := strconv.Atoi(byteCountString)
-			value, _ := strconv.Atoi(valueString)
-
-			state.UseVariable(variable)
-			state.assembler.StoreNumber(variable.Register(), byte(offset), byte(byteCount), uint64(value))
-			return nil
+			return state.StoreMemory(expr)
 		}
 	}
 
+	returnValueRegister := state.registers.ReturnValue[0]
+
+	if function.HasReturnValue() {
+		returnValueRegister = function.ReturnValueRegisters(state.registers)[0]
+	}
+
+	// The destination can be one of the call registers
+	// and needs to be marked as used again after the call.
+	var destinationUser fmt.Stringer
+
+	if expr.Register != nil {
+		destinationUser = expr.Register.User()
+	}
+
 	// Call the function
-	if functionName == BuiltinSyscall {
-		pushRegisters, callRegisters, err := state.BeforeCall(function, parameters)
+	pushRegisters, pushedUsers, callRegisters, err := state.BeforeCall(function, parameters, expr.Register)
 
-		if err != nil {
-			return err
-		}
+	if err != nil {
+		return err
+	}
 
+	switch {
+	case functionName == BuiltinSyscall:
 		state.assembler.Syscall()
-		state.AfterCall(function, pushRegisters, callRegisters)
-	} else {
-		pushRegisters, callRegisters, err := state.BeforeCall(function, parameters)
 
-		if err != nil {
-			return err
-		}
+	// Inline the function call if it's a little function
+	case function.CanInline():
+		function.InlineInto(state.function)
 
-		// Inline the function call if it's a little function
-		if function.CanInline() {
-			function.InlineInto(state.function)
-		} else {
-			state.assembler.Call(functionName)
-		}
+	default:
+		state.assembler.Call(function.Name)
+	}
+
+	// The return value register can be one of the saved registers
+	// if it's also used to pass parameters, e.g. for floating-point numbers.
+	// Restoring it would overwrite the return value, so it needs to be moved first.
+	returnValueSaved := pushRegisters.Contains(returnValueRegister)
 
-		state.AfterCall(function, pushRegisters, callRegisters)
+	if returnValueSaved && expr.Register != nil {
+		state.assembler.MoveRegisterRegister(expr.Register, returnValueRegister)
 	}
 
-	// Mark return value register temporarily as used for better assembly output
-	returnValueRegister := state.registers.ReturnValue[0]
-	err := returnValueRegister.Use(expr)
+	state.AfterCall(function, pushRegisters, pushedUsers, callRegisters)
 
-	if err != nil {
-		return err
+	if destinationUser != nil && expr.Register.IsFree() {
+		expr.Register.ForceUse(destinationUser)
 	}
 
-	// Save return value in temporary register
-	if expr.Register != returnValueRegister {
-		if expr.Register != nil {
-			state.assembler.MoveRegisterRegister(expr.Register, returnValueRegister)
+	if !returnValueSaved {
+		// Mark return value register temporarily as used for better assembly output
+		err = returnValueRegister.Use(expr)
+
+		if err != nil {
+			return err
 		}
 
-		returnValueRegister.Free()
+		// Save return value in temporary register
+		if expr.Register != returnValueRegister {
+			if expr.Register != nil {
+				state.assembler.MoveRegisterRegister(expr.Register, returnValueRegister)
+			}
+
+			returnValueRegister.Free()
+		}
 	}
 
 	if function.HasReturnValue() {
@@ -160,9 +168,13 @@ func (state *State) CallExpression(expr *expression.Expression) error {
 }
 
 // BeforeCall pushes parameters into registers.
-func (state *State) BeforeCall(function *Function, parameters []*expression.Expression) (register.List, register.List, error) {
+// Registers holding temporary values of an outer expression are saved on the stack
+// and their users are returned so that AfterCall can restore them.
+// The destination register receives the return value and therefore doesn't need to be saved.
+func (state *State) BeforeCall(function *Function, parameters []*expression.Expression, destination *register.Register) (register.List, []fmt.Stringer, register.List, error) {
 	// nolint:prealloc
-	var pushRegisters []*register.Register
+	var pushRegisters register.List
+	var pushedUsers []fmt.Stringer
 	var usedRegisterIDs []register.ID
 
 	if function == state.function {
@@ -182,12 +194,65 @@ func (state *State) BeforeCall(function *Function, parameters []*expression.Expr
 		// If the function failed with a compilation error,
 		// we're done here.
 		if function.Error != nil {
-			return nil, nil, function.Error
+			return nil, nil, nil, function.Error
 		}
 
 		usedRegisterIDs = function.UsedRegisterIDs()
 	}
 
+	// Determine which registers to use for our parameters.
+	// Parameters without a register are passed on the stack.
+	var parameterRegisters register.List
+
+	if function.Name == BuiltinSyscall {
+		if len(parameters) > len(state.registers.Syscall) {
+			return nil, nil, nil, errors.New(errors.ExceededMaxParameters)
+		}
+
+		parameterRegisters = state.registers.Syscall[:len(parameters)]
+	} else {
+		parameterRegisters = function.ParameterRegisters(state.registers)
+	}
+
+	var callRegisters register.List
+
+	for _, callRegister := range parameterRegisters {
+		if callRegister != nil {
+			callRegisters = append(callRegisters, callRegister)
+		}
+	}
+
+	// If one of the call registers is used by a variable,
+	// move the variable to another register.
+	for i, callRegister := range parameterRegisters {
+		if callRegister == nil {
+			continue
+		}
+
+		variable, isVariable := callRegister.User().(*Variable)
+
+		if !isVariable {
+			continue
+		}
+
+		// Check if we can skip the move entirely in case our
+		// variable is already inside the correct register.
+		parameter := parameters[i]
+
+		if parameter.IsLeaf() && parameter.Token.Kind == token.Identifier && parameter.Token.Text() == variable.Name {
+			continue
+		}
+
+		freeRegister := state.FindFreeRegisterIn(state.registers.ListFor(callRegister))
+
+		if freeRegister == nil {
+			return nil, nil, nil, errors.New(errors.ExceededMaxVariables)
+		}
+
+		state.assembler.MoveRegisterRegister(freeRegister, callRegister)
+		_ = variable.SetRegister(freeRegister)
+	}
+
 	// Determine the registers we need to save
 	for _, registerID := range usedRegisterIDs {
 		callModifiedRegister := state.registers.ByID(registerID)
@@ -196,33 +261,71 @@ func (state *State) BeforeCall(function *Function, parameters []*expression.Expr
 			continue
 		}
 
-		variable := callModifiedRegister.User().(*Variable)
+		variable, isVariable := callModifiedRegister.User().(*Variable)
 
-		// Don't push variables that are going to die after this instruction
-		if variable.AliveUntil < state.InstructionEndPosition() {
+		if !isVariable && callModifiedRegister == destination {
+			continue
+		}
+
+		// Don't push variables that are going to die after this call
+		if isVariable && variable.AliveUntil < state.InstructionEndPosition() && state.UsedOnlyIn(variable.Name, parameters) {
 			continue
 		}
 
 		pushRegisters = append(pushRegisters, callModifiedRegister)
 	}
 
+	// Call registers holding the temporary values of an outer
+	// expression are overwritten by our parameters.
+	for _, callRegister := range callRegisters {
+		if callRegister.IsFree() || callRegister == destination || pushRegisters.Contains(callRegister) {
+			continue
+		}
+
+		_, isVariable := callRegister.User().(*Variable)
+
+		if isVariable {
+			continue
+		}
+
+		pushRegisters = append(pushRegisters, callRegister)
+	}
+
 	// Save registers
 	for _, reg := range pushRegisters {
 		state.assembler.PushRegister(reg)
+		user := reg.User()
+
+		// Temporary values are restored after the call,
+		// so their registers can be used in the meantime.
+		if _, isVariable := user.(*Variable); !isVariable {
+			reg.Free()
+		}
+
+		pushedUsers = append(pushedUsers, user)
 	}
 
-	// Determine which registers to use for our parameters
-	var callRegisters register.List
+	// Parameters that don't fit into the call registers
+	// are pushed on the stack in reverse order.
+	for i := len(parameters) - 1; i >= 0; i-- {
+		if parameterRegisters[i] != nil {
+			continue
+		}
 
-	if function.Name == BuiltinSyscall {
-		callRegisters = state.registers.Syscall
-	} else {
-		callRegisters = state.registers.Call
+		err := state.PushParameter(function, i, parameters[i])
+
+		if err != nil {
+			return nil, nil, nil, err
+		}
 	}
 
 	// Move parameters into registers
 	for i, parameter := range parameters {
-		callRegister := callRegisters[i]
+		callRegister := parameterRegisters[i]
+
+		if callRegister == nil {
+			continue
+		}
 
 		// Check if we can skip the move entirely in case our
 		// variable is already inside the correct register.
@@ -235,38 +338,25 @@ func (state *State) BeforeCall(function *Function, parameters []*expression.Expr
 			}
 		}
 
-		// If one of the call registers is already in use,
-		// move the current user of the register to another one.
-		if !callRegister.IsFree() {
-			freeRegister := state.registers.General.FindFree()
-
-			if freeRegister == nil {
-				return nil, nil, errors.New(errors.ExceededMaxVariables)
-			}
-
-			state.assembler.MoveRegisterRegister(freeRegister, callRegister)
-			variable, isVariable := callRegister.User().(*Variable)
-
-			if isVariable {
-				_ = variable.SetRegister(freeRegister)
-			} else {
-				panic("This should never happen")
-			}
-
-			callRegister.Free()
-		}
-
 		_ = callRegister.Use(function.Parameters[i])
 
 		// Save the parameter in the call register
 		typ, err := state.ExpressionToRegister(parameter, callRegister)
 
 		if err != nil {
-			return nil, nil, err
+			return nil, nil, nil, err
+		}
+
+		if parameter.IsLeaf() && !function.NoParameterCheck {
+			typ, err = AdaptNumber(parameter.Token, typ, function.Parameters[i].Type)
+
+			if err != nil {
+				return nil, nil, nil, err
+			}
 		}
 
-		if !function.NoParameterCheck && typ != function.Parameters[i].Type {
-			return nil, nil, errors.New(&errors.InvalidType{
+		if !function.NoParameterCheck && !typ.IsAssignableTo(function.Parameters[i].Type) {
+			return nil, nil, nil, errors.New(&errors.InvalidType{
 				Name:          typ.String(),
 				Expected:      function.Parameters[i].Type.String(),
 				ParameterName: function.Parameters[i].Name,
@@ -274,13 +364,82 @@ func (state *State) BeforeCall(function *Function, parameters []*expression.Expr
 		}
 	}
 
-	return pushRegisters, callRegisters, nil
+	return pushRegisters, pushedUsers, callRegisters, nil
+}
+
+// PushParameter evaluates a parameter that doesn't fit into the call registers and pushes it on the stack.
+// The register for the value can be borrowed from a variable because the value is on the stack after the push.
+func (state *State) PushParameter(function *Function, index int, parameter *expression.Expression) error {
+	state.BorrowRegisters()
+	temporary := state.TemporaryRegisterFor(function.Parameters[index].Type)
+
+	if temporary == nil {
+		return errors.New(errors.ExceededMaxVariables)
+	}
+
+	temporary.ForceUse(function.Parameters[index])
+	typ, err := state.ExpressionToRegister(parameter, temporary)
+
+	if err != nil {
+		return err
+	}
+
+	if parameter.IsLeaf() {
+		typ, err = AdaptNumber(parameter.Token, typ, function.Parameters[index].Type)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if !typ.IsAssignableTo(function.Parameters[index].Type) {
+		return errors.New(&errors.InvalidType{
+			Name:          typ.String(),
+			Expected:      function.Parameters[index].Type.String(),
+			ParameterName: function.Parameters[index].Name,
+		})
+	}
+
+	state.assembler.PushRegister(temporary)
+	temporary.Free()
+	return state.ReturnRegisters()
+}
+
+// UsedOnlyIn returns true if every use of the identifier in the current instruction is inside of the parameters.
+// Other uses can be evaluated after the call and need the value of the identifier.
+func (state *State) UsedOnlyIn(identifier string, parameters []*expression.Expression) bool {
+	count := 0
+
+	for _, parameter := range parameters {
+		count += expressionUseCount(parameter, identifier)
+	}
+
+	return count == state.InstructionUseCount(identifier)
+}
+
+// expressionUseCount returns how often the identifier is used in the expression.
+func expressionUseCount(expr *expression.Expression, identifier string) int {
+	count := UseCount(expr.Token, identifier)
+
+	for _, child := range expr.Children {
+		count += expressionUseCount(child, identifier)
+	}
+
+	return count
 }
 
 // AfterCall restores saved registers from the stack.
-func (state *State) AfterCall(function *Function, pushedRegisters []*register.Register, callRegisters []*register.Register) {
+func (state *State) AfterCall(function *Function, pushedRegisters []*register.Register, pushedUsers []fmt.Stringer, callRegisters []*register.Register) {
 	atomic.AddInt32(&function.CallCount, 1)
 
+	// Remove the parameters that were passed on the stack.
+	// Only functions with a parameter check have a fixed number of parameters.
+	stackParameters := len(function.Parameters) - len(callRegisters)
+
+	if stackParameters > 0 && !function.NoParameterCheck {
+		state.assembler.AddRegisterNumber(state.registers.Stack, uint64(stackParameters*8))
+	}
+
 	// Restore saved registers
 	for i := len(pushedRegisters) - 1; i >= 0; i-- {
 		state.assembler.PopRegister(pushedRegisters[i])
@@ -290,6 +449,16 @@ func (state *State) AfterCall(function *Function, pushedRegisters []*register.Re
 	for _, callRegister := range callRegisters {
 		callRegister.Free()
 	}
+
+	// Restore the users of temporary values.
+	// Variables that have been spilled in the meantime don't need their register anymore.
+	for i, reg := range pushedRegisters {
+		if variable, isVariable := pushedUsers[i].(*Variable); isVariable && variable.Register() != reg {
+			continue
+		}
+
+		reg.ForceUse(pushedUsers[i])
+	}
 }
 
 // printLn adds instructions to print a message to the console.
@@ -25,15 +25,6 @@ func Compile(function *Function, environment *Environment, optimize bool, verbos
 
 	registers := register.NewManager()
 	tokens := function.Tokens()
-	identifierLifeTime := IdentifierLifeTimeMap(tokens)
-
-	// Parameters
-	err := declareParameters(function, scopes, registers, identifierLifeTime)
-
-	if err != nil {
-		function.Error = err
-		return
-	}
 
 	// Instructions
 	instructions, instrErr := instruction.FromTokens(tokens)
@@ -43,6 +34,17 @@ func Compile(function *Function, environment *Environment, optimize bool, verbos
 		return
 	}
 
+	identifierLifeTime := IdentifierLifeTimeMap(tokens)
+	ExtendLoopLifeTimes(identifierLifeTime, tokens, instructions, function.Parameters)
+
+	// Parameters
+	stackParameters, err := declareParameters(function, scopes, registers, identifierLifeTime)
+
+	if err != nil {
+		function.Error = err
+		return
+	}
+
 	// Assembler
 	assembler := assembler.New(verbose)
 	assembler.AddLabel(function.Name)
@@ -58,6 +60,7 @@ func Compile(function *Function, environment *Environment, optimize bool, verbos
 		tokens:             tokens,
 		instructions:       instructions,
 		identifierLifeTime: identifierLifeTime,
+		escaping:           EscapingVariables(instructions, environment),
 		ignoreContracts:    false,
 	}
 
@@ -65,18 +68,12 @@ func Compile(function *Function, environment *Environment, optimize bool, verbos
 		state.ignoreContracts = true
 	}
 
-	// Return types
-	if len(function.ReturnTypeTokens) > 0 {
-		typeName := function.ReturnTypeTokens[0].Text()
-		typ := environment.Types[typeName]
+	// Return values
+	err = checkReturnValues(function, registers)
 
-		if typ == nil {
-			err = errors.New(state.environment.UnknownTypeError(typeName))
-			function.Error = NewError(err, function.File.path, function.File.tokens[:function.returnTypeStart+1], function)
-			return
-		}
-
-		function.ReturnTypes = append(function.ReturnTypes, typ)
+	if err != nil {
+		function.Error = err
+		return
 	}
 
 	// Compile the function
@@ -109,7 +106,8 @@ func Compile(function *Function, environment *Environment, optimize bool, verbos
 			Type: state.function.ReturnTypes[0],
 		}
 
-		underscore.ForceSetRegister(registers.ReturnValue[0])
+		returnValueRegister := function.ReturnValueRegisters(registers)[0]
+		underscore.ForceSetRegister(returnValueRegister)
 		state.scopes.Push()
 		state.scopes.Add(underscore)
 
@@ -122,7 +120,7 @@ func Compile(function *Function, environment *Environment, optimize bool, verbos
 			}
 		}
 
-		registers.ReturnValue[0].Free()
+		returnValueRegister.Free()
 	}
 
 	assembler.Return()
@@ -130,7 +128,7 @@ func Compile(function *Function, environment *Environment, optimize bool, verbos
 	// Contract expect failures
 	for _, expect := range state.expectState.list {
 		assembler.AddLabel(expect.failLabel)
-		state.printLn(fmt.Sprintf("%s: expect %v", state.function.Name, expect.condition))
+		state.printLn(fmt.Sprintf("%s: expect %v", UnpolymorphName(state.function.Name), expect.condition))
 		state.assembler.MoveRegisterNumber(state.registers.Syscall[0], 60)
 		state.assembler.MoveRegisterNumber(state.registers.Syscall[1], 1)
 		state.assembler.Syscall()
@@ -139,43 +137,80 @@ func Compile(function *Function, environment *Environment, optimize bool, verbos
 	// Contract ensure failures
 	for _, ensure := range state.ensureState.list {
 		assembler.AddLabel(ensure.failLabel)
-		state.printLn(fmt.Sprintf("%s: ensure %v", state.function.Name, ensure.condition))
+		state.printLn(fmt.Sprintf("%s: ensure %v", UnpolymorphName(state.function.Name), ensure.condition))
 		state.assembler.MoveRegisterNumber(state.registers.Syscall[0], 60)
 		state.assembler.MoveRegisterNumber(state.registers.Syscall[1], 1)
 		state.assembler.Syscall()
 	}
 
+	// Array bounds failures
+	for _, check := range state.boundsState.list {
+		assembler.AddLabel(check.failLabel)
+		state.printLn(fmt.Sprintf("%s: index out of bounds %s", UnpolymorphName(state.function.Name), check.access))
+		state.assembler.MoveRegisterNumber(state.registers.Syscall[0], 60)
+		state.assembler.MoveRegisterNumber(state.registers.Syscall[1], 1)
+		state.assembler.Syscall()
+	}
+
+	// Stack frame for spilled variables and stack parameters, aligned to 16 bytes
+	if state.stackState.size > 0 || stackParameters > 0 {
+		frameSize := (state.stackState.size + 15) &^ 15
+		assembler.AddStackFrame(registers.Frame, registers.Stack, uint64(frameSize))
+	}
+
 	// Optimize assembly code
 	state.assembler.Optimize()
 }
 
 // declareParameters declares the given parameters as variables inside the scope.
-// It also assigns a register to each variable.
-func declareParameters(function *Function, scopes *ScopeStack, registers *register.Manager, identifierLifeTime map[string]token.Position) error {
-	for i, parameter := range function.Parameters {
-		if i >= len(registers.Call) {
-			return errors.New(errors.ExceededMaxParameters)
+// It also assigns a register to each variable and returns the number of parameters passed on the stack.
+func declareParameters(function *Function, scopes *ScopeStack, registers *register.Manager, identifierLifeTime map[string]token.Position) (int, error) {
+	// Parameter types are resolved before compilation
+	for _, parameter := range function.Parameters {
+		if parameter.Type == nil {
+			typeName := parameter.TypeName()
+			file := function.File
+			return 0, NewError(errors.New(&errors.UnknownType{Name: typeName}), file.path, file.tokens[:parameter.Position+2], function)
 		}
+	}
 
-		register := registers.Call[i]
-		typeName := parameter.TypeTokens[0].Text()
-		file := function.File
-		parameter.Type = file.environment.Types[typeName]
+	parameterRegisters := function.ParameterRegisters(registers)
+	stackParameters := 0
 
-		if parameter.Type == nil {
-			return NewError(errors.New(&errors.UnknownType{Name: typeName}), file.path, file.tokens[:parameter.Position+2], function)
+	for i, parameter := range function.Parameters {
+		variable := &Variable{
+			Name:        parameter.Name,
+			Type:        parameter.Type,
+			Position:    0,
+			AliveUntil:  identifierLifeTime[parameter.Name],
+			Mutable:     parameter.Mutable,
+			IsParameter: true,
 		}
 
-		variable := &Variable{
-			Name:       parameter.Name,
-			Type:       parameter.Type,
-			Position:   0,
-			AliveUntil: identifierLifeTime[parameter.Name],
+		// Parameters that don't fit into the call registers are passed on the stack.
+		// They are stored above the saved frame pointer and the return address.
+		if parameterRegisters[i] == nil {
+			variable.stackOffset = int32(16 + stackParameters*8)
+			stackParameters++
+			scopes.Add(variable)
+			continue
 		}
 
-		_ = variable.SetRegister(register)
+		_ = variable.SetRegister(parameterRegisters[i])
 		scopes.Add(variable)
 	}
 
+	return stackParameters, nil
+}
+
+// checkReturnValues makes sure that every return value fits into a return value register.
+func checkReturnValues(function *Function, registers *register.Manager) error {
+	for _, returnValueRegister := range function.ReturnValueRegisters(registers) {
+		if returnValueRegister == nil {
+			file := function.File
+			return NewError(errors.New(errors.ExceededMaxReturnValues), file.path, file.tokens[:function.returnTypeStart+1], function)
+		}
+	}
+
 	return nil
 }
@@ -0,0 +1,429 @@
+package build
+
+import (
+	"fmt"
+
+	"github.com/akyoto/q/build/errors"
+	"github.com/akyoto/q/build/operators"
+	"github.com/akyoto/q/build/register"
+	"github.com/akyoto/q/build/token"
+	"github.com/akyoto/q/build/types"
+)
+
+// ConditionState handles the state of condition compilation.
+type ConditionState struct {
+	counter int
+}
+
+// Condition encodes the jumps for the given condition.
+// The code jumps to elseLabel if the condition is false.
+func (state *State) Condition(condition []token.Token, elseLabel string) error {
+	return state.ConditionJump(condition, elseLabel, false)
+}
+
+// ConditionJump jumps to the label if the condition evaluates to jumpIf.
+// Logical operators are short-circuited: the remaining comparisons
+// are skipped as soon as the result of the condition is known.
+func (state *State) ConditionJump(condition []token.Token, label string, jumpIf bool) error {
+	condition = withoutGroup(condition)
+
+	if len(condition) == 0 {
+		return errors.New(errors.InvalidExpression)
+	}
+
+	// "||" has a lower priority than "&&", therefore it is split first.
+	for _, operator := range []string{"||", "&&"} {
+		parts := splitLogical(condition, operator)
+
+		if len(parts) == 1 {
+			continue
+		}
+
+		// Only some of the parts are evaluated at runtime,
+		// therefore the variables can't be spilled here.
+		state.stackState.locked++
+		defer func() { state.stackState.locked-- }()
+
+		// "||" is known to be true as soon as one part is true.
+		// "&&" is known to be false as soon as one part is false.
+		shortCircuit := operator == "||"
+
+		if jumpIf == shortCircuit {
+			for _, part := range parts {
+				err := state.ConditionJump(part, label, jumpIf)
+
+				if err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}
+
+		state.conditionState.counter++
+		skipLabel := fmt.Sprintf("condition_%d_skip", state.conditionState.counter)
+
+		for _, part := range parts[:len(parts)-1] {
+			err := state.ConditionJump(part, skipLabel, shortCircuit)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		err := state.ConditionJump(parts[len(parts)-1], label, jumpIf)
+
+		if err != nil {
+			return err
+		}
+
+		state.assembler.AddLabel(skipLabel)
+		return nil
+	}
+
+	return state.Comparison(condition, label, jumpIf)
+}
+
+// Comparison encodes a compare instruction followed by a jump
+// to the label if the comparison evaluates to jumpIf.
+func (state *State) Comparison(condition []token.Token, label string, jumpIf bool) error {
+	operatorPos := comparisonPosition(condition)
+
+	if operatorPos == -1 {
+		return state.BoolCondition(condition, label, jumpIf)
+	}
+
+	left := condition[:operatorPos]
+	state.BorrowRegisters()
+	leftRegister, leftType, err := state.EvaluateTokens(left)
+
+	if err != nil {
+		return err
+	}
+
+	if leftType == nil {
+		return errors.New(&errors.CantInferType{Expression: fmt.Sprint(left)})
+	}
+
+	right := condition[operatorPos+1:]
+	temporary, rightType, err := state.CompareRegisterExpression(leftRegister, leftType, right, "")
+
+	if err != nil {
+		return err
+	}
+
+	if rightType == nil {
+		return errors.New(&errors.CantInferType{Expression: fmt.Sprint(right)})
+	}
+
+	if leftType != rightType {
+		return errors.New(&errors.InvalidType{Name: rightType.String(), Expected: leftType.String()})
+	}
+
+	if temporary != nil {
+		temporary.Free()
+	}
+
+	if _, isVariable := leftRegister.User().(*Variable); !isVariable {
+		leftRegister.Free()
+	}
+
+	// Borrowed registers are restored before the jump
+	// so that they are valid on both code paths.
+	err = state.ReturnRegisters()
+
+	if err != nil {
+		return err
+	}
+
+	operator := condition[operatorPos].Text()
+
+	// Floating-point comparisons set the flags like unsigned comparisons
+	unsigned := leftType.IsUnsigned() || leftType.IsFloat()
+
+	if jumpIf {
+		state.IfTrueJump(operator, label, unsigned)
+	} else {
+		state.IfFalseJump(operator, label, unsigned)
+	}
+
+	return nil
+}
+
+// BoolCondition jumps to the label if the boolean value of the condition equals jumpIf.
+func (state *State) BoolCondition(condition []token.Token, label string, jumpIf bool) error {
+	// A negated variable or group inverts the jump
+	if condition[0].Kind == token.Operator && condition[0].Text() == "!" {
+		operand := condition[1:]
+
+		if len(operand) == 1 || len(withoutGroup(operand)) < len(operand) {
+			return state.ConditionJump(operand, label, !jumpIf)
+		}
+	}
+
+	state.BorrowRegisters()
+	register, typ, err := state.EvaluateTokens(condition)
+
+	if err != nil {
+		return err
+	}
+
+	if typ == nil {
+		return errors.New(&errors.CantInferType{Expression: fmt.Sprint(condition)})
+	}
+
+	if typ != types.Bool {
+		return errors.New(&errors.InvalidType{Name: typ.String(), Expected: types.Bool.String()})
+	}
+
+	state.assembler.CompareRegisterNumber(register, 0)
+
+	if _, isVariable := register.User().(*Variable); !isVariable {
+		register.Free()
+	}
+
+	err = state.ReturnRegisters()
+
+	if err != nil {
+		return err
+	}
+
+	if jumpIf {
+		state.assembler.JumpIfNotEqual(label)
+	} else {
+		state.assembler.JumpIfEqual(label)
+	}
+
+	return nil
+}
+
+// IfTrueJump jumps if the previous compare statement was true.
+func (state *State) IfTrueJump(operator string, label string, unsigned bool) {
+	if unsigned {
+		state.IfTrueJumpUnsigned(operator, label)
+		return
+	}
+
+	switch operator {
+	case ">=":
+		state.assembler.JumpIfGreaterOrEqual(label)
+
+	case ">":
+		state.assembler.JumpIfGreater(label)
+
+	case "<=":
+		state.assembler.JumpIfLessOrEqual(label)
+
+	case "<":
+		state.assembler.JumpIfLess(label)
+
+	case "==":
+		state.assembler.JumpIfEqual(label)
+
+	case "!=":
+		state.assembler.JumpIfNotEqual(label)
+	}
+}
+
+// IfFalseJump jumps if the previous compare statement was false.
+func (state *State) IfFalseJump(operator string, label string, unsigned bool) {
+	if unsigned {
+		state.IfFalseJumpUnsigned(operator, label)
+		return
+	}
+
+	switch operator {
+	case ">=":
+		state.assembler.JumpIfLess(label)
+
+	case ">":
+		state.assembler.JumpIfLessOrEqual(label)
+
+	case "<=":
+		state.assembler.JumpIfGreater(label)
+
+	case "<":
+		state.assembler.JumpIfGreaterOrEqual(label)
+
+	case "==":
+		state.assembler.JumpIfNotEqual(label)
+
+	case "!=":
+		state.assembler.JumpIfEqual(label)
+	}
+}
+
+// SetIf sets the register to 1 if the previous compare statement was true and to 0 otherwise.
+func (state *State) SetIf(operator string, register *register.Register, unsigned bool) {
+	if unsigned {
+		state.SetIfUnsigned(operator, register)
+	} else {
+		switch operator {
+		case ">=":
+			state.assembler.SetIfGreaterOrEqual(register)
+
+		case ">":
+			state.assembler.SetIfGreater(register)
+
+		case "<=":
+			state.assembler.SetIfLessOrEqual(register)
+
+		case "<":
+			state.assembler.SetIfLess(register)
+
+		case "==":
+			state.assembler.SetIfEqual(register)
+
+		case "!=":
+			state.assembler.SetIfNotEqual(register)
+		}
+	}
+
+	// SETcc only writes the lowest byte of the register
+	state.assembler.ZeroExtend(register, register, 1)
+}
+
+// SetIfUnsigned sets the lowest byte of the register if the previous unsigned compare statement was true.
+func (state *State) SetIfUnsigned(operator string, register *register.Register) {
+	switch operator {
+	case ">=":
+		state.assembler.SetIfAboveOrEqual(register)
+
+	case ">":
+		state.assembler.SetIfAbove(register)
+
+	case "<=":
+		state.assembler.SetIfBelowOrEqual(register)
+
+	case "<":
+		state.assembler.SetIfBelow(register)
+
+	case "==":
+		state.assembler.SetIfEqual(register)
+
+	case "!=":
+		state.assembler.SetIfNotEqual(register)
+	}
+}
+
+// IfTrueJumpUnsigned jumps if the previous unsigned compare statement was true.
+func (state *State) IfTrueJumpUnsigned(operator string, label string) {
+	switch operator {
+	case ">=":
+		state.assembler.JumpIfAboveOrEqual(label)
+
+	case ">":
+		state.assembler.JumpIfAbove(label)
+
+	case "<=":
+		state.assembler.JumpIfBelowOrEqual(label)
+
+	case "<":
+		state.assembler.JumpIfBelow(label)
+
+	case "==":
+		state.assembler.JumpIfEqual(label)
+
+	case "!=":
+		state.assembler.JumpIfNotEqual(label)
+	}
+}
+
+// IfFalseJumpUnsigned jumps if the previous unsigned compare statement was false.
+func (state *State) IfFalseJumpUnsigned(operator string, label string) {
+	switch operator {
+	case ">=":
+		state.assembler.JumpIfBelow(label)
+
+	case ">":
+		state.assembler.JumpIfBelowOrEqual(label)
+
+	case "<=":
+		state.assembler.JumpIfAbove(label)
+
+	case "<":
+		state.assembler.JumpIfAboveOrEqual(label)
+
+	case "==":
+		state.assembler.JumpIfNotEqual(label)
+
+	case "!=":
+		state.assembler.JumpIfEqual(label)
+	}
+}
+
+// splitLogical splits the condition at every occurrence
+// of the logical operator that is not inside of a group.
+func splitLogical(condition []token.Token, operator string) [][]token.Token {
+	parts := [][]token.Token{}
+	groups := 0
+	start := 0
+
+	for i, t := range condition {
+		switch t.Kind {
+		case token.GroupStart:
+			groups++
+
+		case token.GroupEnd:
+			groups--
+
+		case token.Operator:
+			if groups != 0 || t.Text() != operator {
+				continue
+			}
+
+			parts = append(parts, condition[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(parts, condition[start:])
+}
+
+// comparisonPosition returns the position of the comparison operator
+// that is not inside of a group or -1 if there is none.
+func comparisonPosition(condition []token.Token) int {
+	groups := 0
+
+	for i, t := range condition {
+		switch t.Kind {
+		case token.GroupStart:
+			groups++
+
+		case token.GroupEnd:
+			groups--
+
+		case token.Operator:
+			if groups == 0 && operators.All[t.Text()].Kind == operators.Comparison {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+// withoutGroup removes the brackets surrounding the whole condition.
+func withoutGroup(condition []token.Token) []token.Token {
+	for len(condition) >= 2 && condition[0].Kind == token.GroupStart && condition[len(condition)-1].Kind == token.GroupEnd {
+		groups := 0
+
+		for i, t := range condition {
+			switch t.Kind {
+			case token.GroupStart:
+				groups++
+
+			case token.GroupEnd:
+				groups--
+			}
+
+			if groups == 0 && i != len(condition)-1 {
+				return condition
+			}
+		}
+
+		condition = condition[1 : len(condition)-1]
+	}
+
+	return condition
+}
@@ -0,0 +1,349 @@
+package build
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/akyoto/q/build/errors"
+	"github.com/akyoto/q/build/expression"
+	"github.com/akyoto/q/build/operators"
+	"github.com/akyoto/q/build/token"
+	"github.com/akyoto/q/build/types"
+)
+
+// Constant represents a named integer that is known at compile time.
+// The tokens up to the name of the constant are kept for error messages
+// because unused constants are reported after the files have been closed.
+type Constant struct {
+	Name        string
+	Value       int64
+	File        *File
+	Used        int32
+	declaration []token.Token
+	tokens      []token.Token
+	number      []byte
+	resolving   bool
+}
+
+// AddConstant registers the constant in the environment.
+func (env *Environment) AddConstant(constant *Constant) error {
+	if env.Constants[constant.Name] != nil {
+		return constant.NewError(errors.New(&errors.ConstantAlreadyExists{Name: constant.Name}))
+	}
+
+	env.Constants[constant.Name] = constant
+	return nil
+}
+
+// ResolveConstants calculates the values of all constants.
+// Constants can refer to other constants of the same package
+// and to the constants of imported packages like `sys.O_CREAT`.
+func (env *Environment) ResolveConstants() error {
+	for _, constant := range env.Constants {
+		err := env.ResolveConstant(constant)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ResolveConstant calculates the value of a single constant.
+func (env *Environment) ResolveConstant(constant *Constant) error {
+	if constant.number != nil {
+		return nil
+	}
+
+	if constant.resolving {
+		return constant.NewError(errors.New(errors.ConstantCycle))
+	}
+
+	constant.resolving = true
+	defer func() { constant.resolving = false }()
+
+	expr, err := expression.FromTokens(constant.tokens)
+
+	if err != nil {
+		return constant.NewError(err)
+	}
+
+	defer expr.Close()
+	value, err := env.ConstantValue(constant, expr)
+
+	if err != nil {
+		return constant.NewError(err)
+	}
+
+	constant.Value = value
+	constant.number = []byte(strconv.FormatInt(value, 10))
+	return nil
+}
+
+// ConstantValue calculates the value of an expression in the declaration of a constant.
+func (env *Environment) ConstantValue(constant *Constant, expr *expression.Expression) (int64, error) {
+	if expr.IsLeaf() {
+		switch expr.Token.Kind {
+		case token.Number:
+			if NumberType(expr.Token) != types.Int {
+				return 0, errors.New(&errors.InvalidType{Name: NumberType(expr.Token).String(), Expected: types.Int.String()})
+			}
+
+			return ParseInt(expr.Token.Text())
+
+		case token.Identifier:
+			return env.ConstantReference(constant, packagePrefix(constant.Name)+expr.Token.Text())
+
+		default:
+			return 0, errors.New(&errors.InvalidType{Name: expr.Token.Kind.String(), Expected: types.Int.String()})
+		}
+	}
+
+	if expr.IsFunctionCall || len(expr.Children) != 2 {
+		return 0, errors.New(errors.InvalidExpression)
+	}
+
+	operation := expr.Token.Text()
+	left := expr.Children[0]
+	right := expr.Children[1]
+
+	if operation == "." {
+		if !left.IsLeaf() || !right.IsLeaf() {
+			return 0, errors.New(errors.InvalidExpression)
+		}
+
+		imp := constant.File.imports[left.Token.Text()]
+
+		if imp == nil {
+			return 0, errors.New(&errors.UnknownPackage{Name: left.Token.Text()})
+		}
+
+		atomic.AddInt32(&imp.Used, 1)
+		return env.ConstantReference(constant, imp.Path+"."+right.Token.Text())
+	}
+
+	a, err := env.ConstantValue(constant, left)
+
+	if err != nil {
+		return 0, err
+	}
+
+	b, err := env.ConstantValue(constant, right)
+
+	if err != nil {
+		return 0, err
+	}
+
+	switch operation {
+	case "+":
+		return a + b, nil
+
+	case "-":
+		return a - b, nil
+
+	case "*":
+		return a * b, nil
+
+	case "&":
+		return a & b, nil
+
+	case "|":
+		return a | b, nil
+
+	case "^":
+		return a ^ b, nil
+
+	case "<<":
+		return a << uint64(b), nil
+
+	case ">>":
+		return a >> uint64(b), nil
+
+	case ">>>":
+		return int64(uint64(a) >> uint64(b)), nil
+
+	case "/", "%":
+		if b == 0 {
+			return 0, errors.New(errors.DivisionByZero)
+		}
+
+		if operation == "/" {
+			return a / b, nil
+		}
+
+		return a % b, nil
+
+	default:
+		return 0, errors.New(&errors.UnsupportedOperator{Operator: operation, Type: types.Int.String()})
+	}
+}
+
+// ConstantReference returns the value of the constant with the given full name
+// that is referenced in the declaration of another constant.
+func (env *Environment) ConstantReference(constant *Constant, name string) (int64, error) {
+	other := env.Constants[name]
+
+	if other == nil {
+		return 0, errors.New(&errors.UnknownVariable{Name: name})
+	}
+
+	err := env.ResolveConstant(other)
+
+	if err != nil {
+		return 0, err
+	}
+
+	atomic.AddInt32(&other.Used, 1)
+	return other.Value, nil
+}
+
+// UnusedConstant returns an error for the first constant of the main package that has never been used.
+// Constants of other packages are allowed to be unused.
+func (env *Environment) UnusedConstant() error {
+	var unused []*Constant
+
+	for _, constant := range env.Constants {
+		if packagePrefix(constant.Name) == "" && atomic.LoadInt32(&constant.Used) == 0 {
+			unused = append(unused, constant)
+		}
+	}
+
+	if len(unused) == 0 {
+		return nil
+	}
+
+	sort.Slice(unused, func(a, b int) bool {
+		return unused[a].Name < unused[b].Name
+	})
+
+	return unused[0].NewError(errors.New(&errors.UnusedConstant{Name: unused[0].Name}))
+}
+
+// NewError creates an error at the name of the constant.
+func (constant *Constant) NewError(err error) error {
+	_, hasMetaData := err.(*Error)
+
+	if hasMetaData {
+		return err
+	}
+
+	return NewError(err, constant.File.path, constant.declaration, nil)
+}
+
+// ReplaceConstants replaces the constants in the tokens of an instruction by their values.
+// Package constants like `sys.O_CREAT` consist of 3 tokens and are replaced by the value in brackets
+// so that the token positions of the instruction stay the same.
+// The tokens are only copied when they contain a constant.
+func (state *State) ReplaceConstants(tokens []token.Token) ([]token.Token, error) {
+	replaced := tokens
+	copied := false
+
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+
+		if t.Kind != token.Identifier || isDeclaration(tokens, i) || state.scopes.Get(t.Text()) != nil {
+			continue
+		}
+
+		// Field access and package functions
+		if i > 0 && isOperator(tokens[i-1], ".") {
+			continue
+		}
+
+		// Field names in struct literals
+		if i+1 < len(tokens) && isOperator(tokens[i+1], ":") {
+			continue
+		}
+
+		isQualified := i+2 < len(tokens) && isOperator(tokens[i+1], ".") && tokens[i+2].Kind == token.Identifier
+		end := i
+
+		if isQualified {
+			end = i + 2
+		}
+
+		if end+1 < len(tokens) && tokens[end+1].Kind == token.GroupStart {
+			continue
+		}
+
+		var constant *Constant
+
+		if isQualified {
+			imp := state.function.File.imports[t.Text()]
+
+			if imp == nil {
+				continue
+			}
+
+			constant = state.environment.Constants[imp.Path+"."+tokens[i+2].Text()]
+
+			if constant == nil {
+				continue
+			}
+
+			atomic.AddInt32(&imp.Used, 1)
+		} else {
+			constant = state.environment.Constants[state.function.PackagePrefix()+t.Text()]
+
+			if constant == nil {
+				continue
+			}
+		}
+
+		if end+1 < len(tokens) && tokens[end+1].Kind == token.Operator {
+			operator := operators.All[tokens[end+1].Text()]
+
+			if operator != nil && operator.Kind == operators.Assignment {
+				return nil, errors.New(errors.ConstantAssignment)
+			}
+		}
+
+		atomic.AddInt32(&constant.Used, 1)
+
+		if !copied {
+			replaced = make([]token.Token, len(tokens))
+			copy(replaced, tokens)
+			copied = true
+		}
+
+		number := token.Token{Kind: token.Number, Bytes: constant.number, Position: t.Position}
+
+		if !isQualified {
+			replaced[i] = number
+			continue
+		}
+
+		replaced[i] = token.Token{Kind: token.GroupStart, Bytes: []byte("("), Position: t.Position}
+		replaced[i+1] = number
+		replaced[i+2] = token.Token{Kind: token.GroupEnd, Bytes: []byte(")"), Position: tokens[i+2].Position}
+		i += 2
+	}
+
+	return replaced, nil
+}
+
+// packagePrefix returns the package prefix like `sys.` of a function or constant name.
+// Names in the main package don't have a prefix.
+func packagePrefix(name string) string {
+	name = UnpolymorphName(name)
+	dot := strings.LastIndexByte(name, '.')
+	return name[:dot+1]
+}
+
+// isDeclaration returns true if the identifier at the index is the name of a new variable.
+func isDeclaration(tokens []token.Token, index int) bool {
+	if index == 0 || tokens[index-1].Kind != token.Keyword {
+		return false
+	}
+
+	keyword := tokens[index-1].Text()
+	return keyword == "let" || keyword == "mut"
+}
+
+// isOperator returns true if the token is the given operator.
+func isOperator(t token.Token, symbol string) bool {
+	return t.Kind == token.Operator && t.Text() == symbol
+}
@@ -0,0 +1,21 @@
+package build
+
+import (
+	"github.com/akyoto/q/build/errors"
+	"github.com/akyoto/q/build/token"
+)
+
+// Continue handles continue statements.
+func (state *State) Continue(tokens []token.Token) error {
+	if len(tokens) > 1 {
+		return errors.New(errors.InvalidExpression)
+	}
+
+	if len(state.breakState.stack) == 0 {
+		return errors.New(errors.ContinueOutsideLoop)
+	}
+
+	target := state.breakState.stack[len(state.breakState.stack)-1]
+	state.assembler.Jump(target.labelContinue)
+	return nil
+}
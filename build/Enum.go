@@ -0,0 +1,98 @@
+package build
+
+import (
+	"sort"
+
+	"github.com/akyoto/q/build/errors"
+	"github.com/akyoto/q/build/expression"
+	"github.com/akyoto/q/build/token"
+	"github.com/akyoto/q/build/types"
+	"github.com/akyoto/stringutils/similarity"
+)
+
+// EnumType returns the enum type with the given name or nil if the name doesn't refer to an enum.
+// Variables shadow the enum types with the same name.
+func (state *State) EnumType(name string) *types.Type {
+	if state.scopes.Get(name) != nil {
+		return nil
+	}
+
+	typ := state.environment.Types[state.function.PackagePrefix()+name]
+
+	if typ == nil {
+		typ = state.environment.Types[name]
+	}
+
+	if typ == nil || !typ.IsEnum() {
+		return nil
+	}
+
+	return typ
+}
+
+// EnumValue returns the enum type and the value of an enum case like `Color.Red`.
+// If the expression is not an enum case, the type is nil.
+func (state *State) EnumValue(expr *expression.Expression) (*types.Type, int, error) {
+	if expr.IsLeaf() || expr.IsFunctionCall || expr.Token.Text() != "." {
+		return nil, 0, nil
+	}
+
+	left := expr.Children[0]
+	right := expr.Children[1]
+
+	if !left.IsLeaf() || left.Token.Kind != token.Identifier || !right.IsLeaf() {
+		return nil, 0, nil
+	}
+
+	typ := state.EnumType(left.Token.Text())
+
+	if typ == nil {
+		return nil, 0, nil
+	}
+
+	caseName := right.Token.Text()
+	value := typ.CaseIndex(caseName)
+
+	if value == -1 {
+		return nil, 0, errors.New(UnknownCaseError(caseName, typ))
+	}
+
+	return typ, value, nil
+}
+
+// EnumToRegister moves the value of an enum case into the register of the dot operation.
+func (state *State) EnumToRegister(access *expression.Expression) (bool, error) {
+	typ, value, err := state.EnumValue(access)
+
+	if err != nil || typ == nil {
+		return false, err
+	}
+
+	access.Type = typ
+	state.assembler.MoveRegisterNumber(access.Register, uint64(value))
+	return true, nil
+}
+
+// UnknownCaseError produces an unknown enum case error
+// and tries to guess which case the user was trying to type.
+func UnknownCaseError(caseName string, typ *types.Type) error {
+	knownCases := make([]string, len(typ.Cases))
+	copy(knownCases, typ.Cases)
+
+	// Suggest a case name based on the similarity to known cases
+	sort.Slice(knownCases, func(a, b int) bool {
+		aSimilarity := similarity.JaroWinkler(caseName, knownCases[a])
+		bSimilarity := similarity.JaroWinkler(caseName, knownCases[b])
+		return aSimilarity > bSimilarity
+	})
+
+	if similarity.JaroWinkler(caseName, knownCases[0]) < 0.8 {
+		return &errors.UnknownCase{TypeName: typ.Name, Name: caseName}
+	}
+
+	return &errors.UnknownCase{
+		TypeName:    typ.Name,
+		Name:        caseName,
+		CorrectName: knownCases[0],
+	}
+}
@@ -1,6 +1,8 @@
 package build
 
 import (
+	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 
@@ -11,7 +13,11 @@ import (
 type Environment struct {
 	Packages        map[string]bool
 	Functions       map[string]*Function
+	Overloads       map[string][]*Function
 	Types           map[string]*types.Type
+	Structs         map[string]*Struct
+	Constants       map[string]*Constant
+	Allocator       *Function
 	StandardLibrary string
 }
 
@@ -26,21 +32,66 @@ func NewEnvironment() (*Environment, error) {
 	environment := &Environment{
 		Packages:        map[string]bool{},
 		Functions:       map[string]*Function{},
+		Overloads:       map[string][]*Function{},
 		Types:           types.Default,
+		Structs:         map[string]*Struct{},
+		Constants:       map[string]*Constant{},
 		StandardLibrary: standardLibrary,
 	}
 
 	return environment, nil
 }
 
+// Type returns the type with the given name or nil if it doesn't exist.
+// Array types like [16]Int and pointer types like *Int are created when they are needed.
+func (env *Environment) Type(name string) *types.Type {
+	if strings.HasPrefix(name, "*") {
+		element := env.Type(name[1:])
+
+		if element == nil {
+			return nil
+		}
+
+		return types.PointerTo(element)
+	}
+
+	if !strings.HasPrefix(name, "[") {
+		return env.Types[name]
+	}
+
+	end := strings.IndexByte(name, ']')
+
+	if end == -1 {
+		return nil
+	}
+
+	length, err := strconv.ParseUint(name[1:end], 10, 32)
+
+	if err != nil || length == 0 {
+		return nil
+	}
+
+	element := env.Type(name[end+1:])
+
+	// Array elements must fit into a single register.
+	if element == nil || element.IsArray() || len(element.Fields) > 0 {
+		return nil
+	}
+
+	return types.Array(element, uint(length))
+}
+
 // ImportDirectory imports a directory to the environment.
 func (env *Environment) ImportDirectory(directory string, prefix string) error {
-	functions, structs, imports, errors := FindFunctions(directory, env)
-	return env.Import(prefix, functions, structs, imports, errors)
+	functions, structs, constants, imports, errors := FindFunctions(directory, env)
+	return env.Import(prefix, functions, structs, constants, imports, errors)
 }
 
 // Import imports the given functions and imports to the environment.
-func (env *Environment) Import(prefix string, functions <-chan *Function, structs <-chan *types.Type, imports <-chan *Import, errors <-chan error) error {
+// Functions are registered by ResolveFunctions after all files have been imported.
+// The field types of structs are resolved by ResolveTypes.
+// The values of constants are calculated by ResolveConstants.
+func (env *Environment) Import(prefix string, functions <-chan *Function, structs <-chan *Struct, constants <-chan *Constant, imports <-chan *Import, errors <-chan error) error {
 	for {
 		select {
 		case err, ok := <-errors:
@@ -64,13 +115,26 @@ func (env *Environment) Import(prefix string, functions <-chan *Function, struct
 				return err
 			}
 
-		case typ, ok := <-structs:
+		case structure, ok := <-structs:
 			if !ok {
 				return nil
 			}
 
-			typ.Name = prefix + typ.Name
-			env.Types[typ.Name] = typ
+			structure.Type.Name = prefix + structure.Type.Name
+			env.Types[structure.Type.Name] = structure.Type
+			env.Structs[structure.Type.Name] = structure
+
+		case constant, ok := <-constants:
+			if !ok {
+				return nil
+			}
+
+			constant.Name = prefix + constant.Name
+			err := env.AddConstant(constant)
+
+			if err != nil {
+				return err
+			}
 
 		case function, ok := <-functions:
 			if !ok {
@@ -78,13 +142,15 @@ func (env *Environment) Import(prefix string, functions <-chan *Function, struct
 			}
 
 			function.Name = prefix + function.Name
-			env.Functions[function.Name] = function
+			env.Overloads[function.Name] = append(env.Overloads[function.Name], function)
 		}
 	}
 }
 
 // Compile compiles all functions.
 func (env *Environment) Compile(optimize bool, verbose bool) {
+	env.Allocator = NewAllocator(verbose)
+	env.ResolveEscapes()
 	wg := sync.WaitGroup{}
 
 	for _, function := range env.Functions {
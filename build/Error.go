@@ -64,7 +64,7 @@ func (e *Error) Error() string {
 	}
 
 	if e.Function != nil {
-		return fmt.Sprintf("%s:%d:%d: [%s] %s", path, e.Line, e.Column, e.Function.Name, e.Err)
+		return fmt.Sprintf("%s:%d:%d: [%s] %s", path, e.Line, e.Column, UnpolymorphName(e.Function.Name), e.Err)
 	}
 
 	return fmt.Sprintf("%s:%d:%d: %s", path, e.Line, e.Column, e.Err)
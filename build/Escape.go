@@ -0,0 +1,224 @@
+package build
+
+import (
+	"strings"
+
+	"github.com/akyoto/q/build/expression"
+	"github.com/akyoto/q/build/instruction"
+	"github.com/akyoto/q/build/token"
+	"github.com/akyoto/q/build/types"
+)
+
+// maxStackStruct is the maximum size in bytes of a struct that is stored in the stack frame.
+const maxStackStruct = 256
+
+// EscapingVariables returns the names of the variables whose structs can't be stored in the stack frame.
+// A variable escapes if its address is taken with `&` because the pointer could be stored anywhere,
+// or if its memory is shared with another variable like in `q = p` or `q = line.start`,
+// because the stack memory of a struct literal is reused every time the literal is executed in a loop.
+// Passing a variable to a parameter that escapes in the called function also lets the variable escape.
+// Returned structs are copied to the heap and don't need to be handled here.
+func EscapingVariables(instructions []instruction.Instruction, environment *Environment) map[string]bool {
+	escaping := map[string]bool{}
+
+	for _, instr := range instructions {
+		tokens := instr.Tokens
+
+		for i, t := range tokens {
+			if t.Kind == token.Operator && t.Text() == "&" && i+1 < len(tokens) && tokens[i+1].Kind == token.Identifier {
+				escaping[tokens[i+1].Text()] = true
+			}
+
+			if t.Kind == token.Identifier && i+1 < len(tokens) && tokens[i+1].Kind == token.GroupStart {
+				environment.escapingArguments(tokens, i, escaping)
+			}
+		}
+
+		if instr.Kind != instruction.Assignment {
+			continue
+		}
+
+		for i, t := range tokens {
+			if t.Kind != token.Operator || t.Text() != "=" {
+				continue
+			}
+
+			value := tokens[i+1:]
+
+			if isAccessorChain(value) {
+				escaping[value[0].Text()] = true
+			}
+
+			break
+		}
+	}
+
+	return escaping
+}
+
+// escapingArguments marks the variables passed to the call at the given index
+// as escaping if the called function lets the parameter escape.
+// The function is not resolved yet, therefore every function with a matching name is checked.
+// In method calls like `p.move()` the receiver is passed as the first parameter.
+func (env *Environment) escapingArguments(tokens []token.Token, index int, escaping map[string]bool) {
+	name := tokens[index].Text()
+	end := index + 1
+	groupLevel := 0
+
+	for ; end < len(tokens); end++ {
+		if tokens[end].Kind == token.GroupStart {
+			groupLevel++
+		}
+
+		if tokens[end].Kind == token.GroupEnd {
+			groupLevel--
+
+			if groupLevel == 0 {
+				break
+			}
+		}
+	}
+
+	if end == len(tokens) {
+		return
+	}
+
+	arguments := token.Split(tokens[index+2:end], token.Separator)
+	var receiver []token.Token
+
+	if index >= 2 && tokens[index-1].Kind == token.Operator && tokens[index-1].Text() == "." {
+		start := index - 2
+
+		for start >= 2 && tokens[start-1].Kind == token.Operator && tokens[start-1].Text() == "." && tokens[start-2].Kind == token.Identifier {
+			start -= 2
+		}
+
+		receiver = tokens[start : index-1]
+	}
+
+	for functionName, overloads := range env.Overloads {
+		if functionName != name && !strings.HasSuffix(functionName, "."+name) {
+			continue
+		}
+
+		for _, function := range overloads {
+			parameters := function.escapes
+			offset := 0
+
+			if function.IsMethod && receiver != nil {
+				if len(parameters) > 0 && parameters[0] && isAccessorChain(receiver) {
+					escaping[receiver[0].Text()] = true
+				}
+
+				offset = 1
+			}
+
+			for i, argument := range arguments {
+				if i+offset < len(parameters) && parameters[i+offset] && isAccessorChain(argument) {
+					escaping[argument[0].Text()] = true
+				}
+			}
+		}
+	}
+}
+
+// ResolveEscapes determines the parameters that escape in each function.
+// Parameters that are passed to an escaping parameter of another function escape as well,
+// therefore the analysis is repeated until no more parameters are found.
+func (env *Environment) ResolveEscapes() {
+	bodies := map[*Function][]instruction.Instruction{}
+
+	for _, function := range env.Functions {
+		instructions, err := instruction.FromTokens(function.Tokens())
+
+		// Syntax errors are reported when the function is compiled.
+		if err != nil {
+			continue
+		}
+
+		bodies[function] = instructions
+		function.escapes = make([]bool, len(function.Parameters))
+	}
+
+	for changed := true; changed; {
+		changed = false
+
+		for function, instructions := range bodies {
+			escaping := EscapingVariables(instructions, env)
+
+			for i, parameter := range function.Parameters {
+				if escaping[parameter.Name] && !function.escapes[i] {
+					function.escapes[i] = true
+					changed = true
+				}
+			}
+		}
+	}
+}
+
+// isAccessorChain returns true if the tokens only consist of a variable name
+// followed by field names like `line.start`.
+func isAccessorChain(tokens []token.Token) bool {
+	if len(tokens) == 0 || len(tokens)%2 == 0 {
+		return false
+	}
+
+	for i, t := range tokens {
+		if i%2 == 0 && t.Kind != token.Identifier {
+			return false
+		}
+
+		if i%2 == 1 && (t.Kind != token.Operator || t.Text() != ".") {
+			return false
+		}
+	}
+
+	return true
+}
+
+// IsStackAllocation returns true if the struct literal can be stored in the stack frame of the function.
+// Literals that are copied into other structs or passed as a parameter don't outlive the function.
+// Literals assigned to a variable are stored on the stack if the variable doesn't escape.
+// Returned literals and big structs are allocated on the heap.
+func (state *State) IsStackAllocation(literal *expression.Expression, typ *types.Type) bool {
+	if typ.Size > maxStackStruct {
+		return false
+	}
+
+	if literal.Parent != nil {
+		return !literal.Parent.IsUnary() || literal.Parent.Token.Text() != "&"
+	}
+
+	if literal.Register == nil {
+		return true
+	}
+
+	switch user := literal.Register.User().(type) {
+	case *Variable:
+		return !state.escaping[user.Name]
+
+	case spilledValue:
+		return !state.escaping[user.variable.Name]
+
+	default:
+		return false
+	}
+}
+
+// IsAllocation returns true if the expression creates a new struct
+// which is the case for struct literals and function calls.
+func IsAllocation(tokens []token.Token) bool {
+	expr, err := expression.FromTokens(tokens)
+
+	if err != nil {
+		return false
+	}
+
+	defer expr.Close()
+
+	if expr.IsFunctionCall {
+		return true
+	}
+
+	return expr.Token.Kind == token.Operator && expr.Token.Text() == "." && expr.Children[1].IsFunctionCall
+}
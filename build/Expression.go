@@ -2,10 +2,12 @@ package build
 
 import (
 	"fmt"
+	"math"
 	"sync/atomic"
 
 	"github.com/akyoto/q/build/errors"
 	"github.com/akyoto/q/build/expression"
+	"github.com/akyoto/q/build/operators"
 	"github.com/akyoto/q/build/register"
 	"github.com/akyoto/q/build/token"
 	"github.com/akyoto/q/build/types"
@@ -13,6 +15,7 @@ import (
 )
 
 // EvaluateTokens evaluates the token expression and stores the result in a register.
+// If the result is stored in a temporary register, the caller needs to free it.
 func (state *State) EvaluateTokens(tokens []token.Token) (*register.Register, *types.Type, error) {
 	if len(tokens) == 1 && tokens[0].Kind == token.Identifier {
 		variableName := tokens[0].Text()
@@ -22,16 +25,20 @@ func (state *State) EvaluateTokens(tokens []token.Token) (*register.Register, *t
 			return nil, nil, errors.New(state.UnknownVariableError(variableName))
 		}
 
-		state.UseVariable(variable)
-		return variable.Register(), variable.Type, nil
+		if !variable.IsSpilled() {
+			state.UseVariable(variable)
+			state.AddOperand(variable)
+			return variable.Register(), variable.Type, nil
+		}
 	}
 
-	freeRegister := state.registers.General.FindFree()
+	freeRegister := state.TemporaryRegisterFor(state.TokensType(tokens))
 
 	if freeRegister == nil {
 		return nil, nil, errors.New(errors.ExceededMaxVariables)
 	}
 
+	freeRegister.ForceUse(token.List(tokens))
 	typ, err := state.TokensToRegister(tokens, freeRegister)
 	return freeRegister, typ, err
 }
@@ -66,22 +73,64 @@ func (state *State) ExpressionToRegister(root *expression.Expression, finalRegis
 		return nil, err
 	}
 
+	if isLogical(root) {
+		return state.LogicalToRegister(root, finalRegister)
+	}
+
 	// Save the temporary registers so we can easily free them later
 	var temporaryRegisters []*register.Register
 
+	// Temporary values can borrow the registers of variables that are not needed by the expression
+	state.BorrowRegisters()
+
 	// Sort by expression complexity so that we can
 	// calculate the most complex expression first.
 	// This reduces the number of registers required.
 	root.SortByRegisterCount()
 
+	// The register that receives the result after the calculation
+	var resultRegister *register.Register
+
 	if finalRegister != nil {
+		left := root
+
+		for len(left.Children) > 0 {
+			left = left.Children[0]
+		}
+
+		// If the expression still needs the current value of the final register
+		// after the first operand has been moved to it, calculate in a temporary register.
+		if state.ReadsRegister(root, finalRegister, left) {
+			temporary := state.TemporaryRegisterIn(state.registers.ListFor(finalRegister))
+
+			if temporary == nil {
+				return nil, errors.New(errors.ExceededMaxVariables)
+			}
+
+			_ = temporary.Use(root)
+			temporaryRegisters = append(temporaryRegisters, temporary)
+			resultRegister = finalRegister
+			finalRegister = temporary
+		}
+
 		root.Register = finalRegister
 
-		// Assign final register to the left operands in the left tree
-		left := root
+		// Assign final register to the left operands in the left tree.
+		// Operands that need a different kind of register, like the address
+		// of a floating-point struct field, are calculated in a temporary register.
+		left = root
 
 		for len(left.Children) > 0 {
 			left = left.Children[0]
+
+			if !left.IsLeaf() || left.Token.Kind != token.Number {
+				typ := state.ExpressionType(left)
+
+				if typ != nil && typ.IsFloat() != finalRegister.IsFloat() {
+					break
+				}
+			}
+
 			left.Register = finalRegister
 		}
 	}
@@ -91,7 +140,7 @@ func (state *State) ExpressionToRegister(root *expression.Expression, finalRegis
 		if sub.IsFunctionCall {
 			// Allocate a temporary register if necessary
 			if sub.Register == nil && sub.Parent != nil {
-				sub.Register = state.registers.General.FindFree()
+				sub.Register = state.TemporaryRegisterFor(state.ExpressionType(sub))
 
 				if sub.Register == nil {
 					return errors.New(errors.ExceededMaxVariables)
@@ -105,11 +154,42 @@ func (state *State) ExpressionToRegister(root *expression.Expression, finalRegis
 		}
 
 		left := sub.Children[0]
-		right := sub.Children[1]
+		operator := sub.Token.Text()
+		isAccess := operator == "." || operator == "["
+		isComparison := isComparison(sub)
+		isDereference := operator == "*" && sub.IsUnary()
+
+		// Address of a struct, a struct field or an array element
+		if operator == "&" && sub.IsUnary() {
+			if sub.Register == nil {
+				sub.Register = state.TemporaryRegisterIn(state.registers.General)
+
+				if sub.Register == nil {
+					return errors.New(errors.ExceededMaxVariables)
+				}
+
+				_ = sub.Register.Use(sub)
+				temporaryRegisters = append(temporaryRegisters, sub.Register)
+			}
+
+			return state.AddressToRegister(sub)
+		}
 
-		// Allocate a temporary register if necessary
+		// Allocate a temporary register if necessary.
+		// Number literals adapt to the type of the operation
+		// or to the type of the other operand in comparisons.
 		if left.Register == nil {
-			left.Register = state.registers.General.FindFree()
+			typ := state.ExpressionType(left)
+
+			if !isAccess && left.IsLeaf() && left.Token.Kind == token.Number {
+				if isComparison {
+					typ = state.ExpressionType(sub.Children[1])
+				} else {
+					typ = state.ExpressionType(sub)
+				}
+			}
+
+			left.Register = state.TemporaryRegisterFor(typ)
 
 			if left.Register == nil {
 				return errors.New(errors.ExceededMaxVariables)
@@ -119,49 +199,79 @@ func (state *State) ExpressionToRegister(root *expression.Expression, finalRegis
 			temporaryRegisters = append(temporaryRegisters, left.Register)
 		}
 
-		sub.Register = left.Register
-		operator := sub.Token.Text()
+		if sub.Register == nil {
+			sub.Register = left.Register
 
-		// Struct field access
-		if operator == "." {
-			variableName := left.Token.Text()
-			variable := state.scopes.Get(variableName)
+			// Floating-point values are loaded from an address in a general purpose register
+			// and the result of a floating-point comparison is stored in a general purpose register.
+			if typ := state.ExpressionType(sub); (isAccess || isDereference || isComparison) && typ != nil && typ.IsFloat() != left.Register.IsFloat() {
+				sub.Register = state.TemporaryRegisterFor(typ)
 
-			if variable == nil {
-				return errors.New(state.UnknownVariableError(variableName))
+				if sub.Register == nil {
+					return errors.New(errors.ExceededMaxVariables)
+				}
+
+				_ = sub.Register.Use(sub)
+				temporaryRegisters = append(temporaryRegisters, sub.Register)
 			}
+		}
 
-			state.UseVariable(variable)
-			fieldName := right.Token.Text()
-			field := variable.Type.FieldByName(fieldName)
+		// Struct field access
+		if operator == "." {
+			return state.FieldToRegister(sub)
+		}
 
-			if field == nil {
-				return errors.New(UnknownFieldError(fieldName, variable.Type))
-			}
+		// Array element access
+		if operator == "[" {
+			return state.ElementToRegister(sub)
+		}
 
-			sub.Type = field.Type
-			state.assembler.LoadRegister(sub.Register, variable.Register(), byte(field.Offset), byte(field.Type.Size))
-			return nil
+		// Pointer dereference
+		if isDereference {
+			return state.DereferenceToRegister(sub)
+		}
+
+		// Comparison
+		if isComparison {
+			return state.ComparisonToRegister(sub)
 		}
 
 		// Left operand
-		if left.IsLeaf() {
+		if left.IsLeaf() && left.Token.Kind == token.Number && sub.Register.IsFloat() {
+			left.Type = state.ExpressionType(sub)
+			err := state.FloatNumberToRegister(left.Token, left.Type, sub.Register)
+
+			if err != nil {
+				return err
+			}
+		} else if left.IsLeaf() {
 			typ, err := state.TokenToRegister(left.Token, sub.Register)
 
 			if err != nil {
 				return err
 			}
 
-			left.Type = typ
+			left.Type, err = AdaptNumber(left.Token, typ, state.ExpressionType(sub))
+
+			if err != nil {
+				return err
+			}
 		} else if sub.Register != left.Register {
 			state.assembler.MoveRegisterRegister(sub.Register, left.Register)
-			left.Register.Free()
+			temporaryRegisters = freeTemporary(temporaryRegisters, left.Register)
 		}
 
 		if sub.Type == nil {
 			sub.Type = left.Type
 		}
 
+		// Negation
+		if operator == "!" {
+			return state.Negate(sub)
+		}
+
+		right := sub.Children[1]
+
 		// Right operand is a leaf node
 		if right.IsLeaf() {
 			switch right.Token.Kind {
@@ -175,35 +285,344 @@ func (state *State) ExpressionToRegister(root *expression.Expression, finalRegis
 
 				state.UseVariable(variable)
 				right.Type = variable.Type
-				return state.CalculateRegisterRegister(operator, sub.Register, variable.Register())
+				variableRegister, release, err := state.VariableOperand(variable, sub.Register)
+
+				if err != nil {
+					return err
+				}
+
+				defer release()
+				return state.Calculate(operator, sub.Type, right.Type, sub.Register, variableRegister)
+
+			case token.Keyword:
+				temporary := state.TemporaryRegisterIn(state.registers.General)
+
+				if temporary == nil {
+					return errors.New(errors.ExceededMaxVariables)
+				}
+
+				temporary.ForceUse(right)
+				defer temporary.Free()
+				typ, err := state.TokenToRegister(right.Token, temporary)
+
+				if err != nil {
+					return err
+				}
+
+				right.Type = typ
+				return state.Calculate(operator, sub.Type, right.Type, sub.Register, temporary)
 
 			case token.Number:
-				right.Type = types.Int
-				return state.CalculateRegisterNumber(operator, sub.Register, right)
+				if sub.Type.IsFloat() {
+					right.Type = sub.Type
+					return state.CalculateFloatNumber(operator, sub.Type, sub.Register, right)
+				}
+
+				if sub.Type.IsPointer() {
+					right.Type = types.Int
+					return state.CalculatePointerNumber(operator, sub.Type, sub.Register, right)
+				}
+
+				typ, err := AdaptNumber(right.Token, NumberType(right.Token), sub.Type)
+
+				if err != nil {
+					return err
+				}
+
+				right.Type = typ
+
+				if right.Type != sub.Type && !isShift(operator) {
+					return errors.New(&errors.InvalidType{Name: right.Type.String(), Expected: sub.Type.String()})
+				}
+
+				return state.CalculateIntegerNumber(operator, sub.Type, sub.Register, right)
 
 			default:
 				return fmt.Errorf("Invalid operand %s", right.Token)
 			}
 		}
 
-		// Right operand is an expression
-		return state.CalculateRegisterRegister(operator, sub.Register, right.Register)
+		// Right operand is an expression.
+		// Its temporary register is not needed after the calculation.
+		err := state.Calculate(operator, sub.Type, right.Type, sub.Register, right.Register)
+		temporaryRegisters = freeTemporary(temporaryRegisters, right.Register)
+		return err
 	})
 
 	if err != nil {
 		return nil, err
 	}
 
+	if resultRegister != nil {
+		state.assembler.MoveRegisterRegister(resultRegister, finalRegister)
+	}
+
 	// Free temporary registers
 	for _, reg := range temporaryRegisters {
 		reg.Free()
 	}
 
-	return root.Type, nil
+	return root.Type, state.ReturnRegisters()
+}
+
+// freeTemporary frees the register if it is one of the temporary registers
+// and removes it from the list so that it isn't freed again after it has been reused.
+func freeTemporary(temporaryRegisters []*register.Register, reg *register.Register) []*register.Register {
+	for i, temporary := range temporaryRegisters {
+		if temporary == reg {
+			reg.Free()
+			return append(temporaryRegisters[:i], temporaryRegisters[i+1:]...)
+		}
+	}
+
+	return temporaryRegisters
+}
+
+// Calculate performs an operation on two registers holding values of the given types.
+func (state *State) Calculate(operation string, typ *types.Type, operandType *types.Type, registerTo *register.Register, registerFrom *register.Register) error {
+	switch {
+	case typ.IsFloat() || operandType.IsFloat():
+		return state.CalculateFloat(operation, typ, operandType, registerTo, registerFrom)
+
+	case typ.IsPointer():
+		return state.CalculatePointer(operation, typ, operandType, registerTo, registerFrom)
+
+	case typ.IsEnum():
+		return errors.New(&errors.UnsupportedOperator{Operator: operation, Type: typ.Name})
+
+	case typ == types.Bool || operators.All[operation].Kind == operators.Logical:
+		return state.CalculateBool(operation, typ, operandType, registerTo, registerFrom)
+
+	default:
+		return state.CalculateInteger(operation, typ, operandType, registerTo, registerFrom)
+	}
+}
+
+// ExpressionType infers the type of an expression without generating any code.
+// It returns nil if the type can't be determined.
+func (state *State) ExpressionType(expr *expression.Expression) *types.Type {
+	if expr.IsLeaf() {
+		switch expr.Token.Kind {
+		case token.Identifier:
+			variable := state.scopes.Get(expr.Token.Text())
+
+			if variable == nil {
+				return nil
+			}
+
+			return variable.Type
+
+		case token.Number:
+			return NumberType(expr.Token)
+
+		case token.Text:
+			return types.Text
+
+		case token.Keyword:
+			_, err := BoolValue(expr.Token)
+
+			if err != nil {
+				return nil
+			}
+
+			return types.Bool
+
+		default:
+			return nil
+		}
+	}
+
+	if expr.IsStructLiteral {
+		return state.environment.Type(expr.Token.Text())
+	}
+
+	if expr.IsFunctionCall {
+		function, _, err := state.ResolveCall(expr)
+
+		if err != nil {
+			return nil
+		}
+
+		if function == nil {
+			return state.environment.Type(expr.Token.Text())
+		}
+
+		if !function.HasReturnValue() {
+			return nil
+		}
+
+		return function.ReturnTypes[0]
+	}
+
+	if expr.Token.Text() == "!" || isComparison(expr) || isLogical(expr) {
+		return types.Bool
+	}
+
+	if expr.IsUnary() && expr.Token.Text() == "&" {
+		return state.AddressType(expr.Children[0])
+	}
+
+	if enumType, _, _ := state.EnumValue(expr); enumType != nil {
+		return enumType
+	}
+
+	left := state.ExpressionType(expr.Children[0])
+
+	if left == nil {
+		return nil
+	}
+
+	if expr.IsUnary() && expr.Token.Text() == "*" {
+		if !left.IsPointer() {
+			return nil
+		}
+
+		return left.Element
+	}
+
+	switch expr.Token.Text() {
+	case ".":
+		if left == types.Text {
+			return TextFieldType(expr.Children[1].Token.Text())
+		}
+
+		field := left.FieldByName(expr.Children[1].Token.Text())
+
+		if field == nil {
+			return nil
+		}
+
+		return field.Type
+
+	case "[":
+		return left.Element
+
+	default:
+		// Number literals adapt to the type of the other operand
+		if expr.Children[0].IsLeaf() && expr.Children[0].Token.Kind == token.Number {
+			right := state.ExpressionType(expr.Children[1])
+
+			if right.IsFloat() || (left == types.Int && right.IsInteger()) {
+				return right
+			}
+		}
+
+		return left
+	}
+}
+
+// TokensType infers the type of a token expression without generating any code.
+// It returns nil if the type can't be determined.
+func (state *State) TokensType(tokens []token.Token) *types.Type {
+	expr, err := expression.FromTokens(tokens)
+
+	if err != nil {
+		return nil
+	}
+
+	defer expr.Close()
+	err = state.ResolveAccessors(expr)
+
+	if err != nil {
+		return nil
+	}
+
+	return state.ExpressionType(expr)
+}
+
+// FieldToRegister loads the struct field of a dot operation into the register of the operation.
+func (state *State) FieldToRegister(access *expression.Expression) error {
+	isEnum, err := state.EnumToRegister(access)
+
+	if err != nil || isEnum {
+		return err
+	}
+
+	left := access.Children[0]
+	right := access.Children[1]
+	structRegister := left.Register
+	structType := left.Type
+
+	if left.IsLeaf() {
+		variableName := left.Token.Text()
+		variable := state.scopes.Get(variableName)
+
+		if variable == nil {
+			return errors.New(state.UnknownVariableError(variableName))
+		}
+
+		state.UseVariable(variable)
+		state.AddOperand(variable)
+		structRegister = variable.Register()
+		structType = variable.Type
+
+		if variable.IsSpilled() {
+			structRegister = access.Register
+
+			// The address can't be stored in a floating-point register
+			if structRegister.IsFloat() {
+				structRegister = left.Register
+			}
+
+			state.LoadSpilled(variable, structRegister)
+		}
+	}
+
+	fieldName := right.Token.Text()
+
+	if structType == types.Text {
+		return state.TextFieldToRegister(access, structRegister)
+	}
+
+	field := structType.FieldByName(fieldName)
+
+	if field == nil {
+		return errors.New(UnknownFieldError(fieldName, structType))
+	}
+
+	access.Type = field.Type
+
+	// Arrays and structs are stored inside of the struct,
+	// therefore the field refers to their address.
+	if field.Type.IsArray() || len(field.Type.Fields) > 0 {
+		if access.Register != structRegister {
+			state.assembler.MoveRegisterRegister(access.Register, structRegister)
+		}
+
+		if field.Offset != 0 {
+			state.assembler.AddRegisterNumber(access.Register, uint64(field.Offset))
+		}
+
+		return nil
+	}
+
+	state.Load(access.Register, structRegister, int32(field.Offset), field.Type)
+	return nil
+}
+
+// ReadsRegister returns true if a leaf of the expression other than the ignored one
+// reads a variable that is stored in the given register.
+func (state *State) ReadsRegister(expr *expression.Expression, register *register.Register, ignore *expression.Expression) bool {
+	if expr.IsLeaf() {
+		if expr == ignore || expr.Token.Kind != token.Identifier {
+			return false
+		}
+
+		variable := state.scopes.Get(expr.Token.Text())
+		return variable != nil && variable.Register() == register
+	}
+
+	for _, child := range expr.Children {
+		if state.ReadsRegister(child, register, ignore) {
+			return true
+		}
+	}
+
+	return false
 }
 
 // TokenToRegister moves a token into a register.
-// It only works with identifiers, numbers and texts.
+// It only works with identifiers, numbers, texts and the boolean keywords.
 func (state *State) TokenToRegister(singleToken token.Token, register *register.Register) (*types.Type, error) {
 	switch singleToken.Kind {
 	case token.Identifier:
@@ -216,15 +635,31 @@ func (state *State) TokenToRegister(singleToken token.Token, register *register.
 
 		state.UseVariable(variable)
 
+		if variable.IsSpilled() {
+			state.LoadSpilled(variable, register)
+			return variable.Type, nil
+		}
+
 		// Moving a variable into its own register is pointless
 		if variable.Register() == register {
-			return nil, nil
+			return variable.Type, nil
 		}
 
 		state.assembler.MoveRegisterRegister(register, variable.Register())
 		return variable.Type, nil
 
 	case token.Number:
+		typ := NumberType(singleToken)
+
+		if register.IsFloat() {
+			err := state.FloatNumberToRegister(singleToken, types.Float, register)
+			return types.Float, err
+		}
+
+		if typ != types.Int {
+			return nil, errors.New(&errors.InvalidType{Name: typ.String(), Expected: types.Int.String()})
+		}
+
 		numberString := singleToken.Text()
 		number, err := state.ParseInt(numberString)
 
@@ -236,16 +671,28 @@ func (state *State) TokenToRegister(singleToken token.Token, register *register.
 		return types.Int, nil
 
 	case token.Text:
-		address := state.assembler.AddString(singleToken.Text())
+		// Braces are only used for values in printed texts,
+		// everywhere else they are part of the text.
+		address := state.assembler.AddText(singleToken.Text())
 		state.assembler.MoveRegisterAddress(register, address)
 		return types.Text, nil
+
+	case token.Keyword:
+		value, err := BoolValue(singleToken)
+
+		if err != nil {
+			return nil, err
+		}
+
+		state.assembler.MoveRegisterNumber(register, value)
+		return types.Bool, nil
 	}
 
 	return nil, errors.New(errors.NotImplemented)
 }
 
 // CalculateRegisterNumber performs an operation on a register and a number.
-func (state *State) CalculateRegisterNumber(operation string, register *register.Register, operand *expression.Expression) error {
+func (state *State) CalculateRegisterNumber(operation string, typ *types.Type, register *register.Register, operand *expression.Expression) error {
 	number, err := state.ParseInt(operand.Token.Text())
 
 	if err != nil {
@@ -253,41 +700,67 @@ func (state *State) CalculateRegisterNumber(operation string, register *register
 	}
 
 	switch operation {
-	case "+":
-		if number == 1 {
-			state.assembler.IncreaseRegister(register)
-			return nil
+	case "+", "-":
+		// The immediate value is sign-extended from 32 bits.
+		if number < math.MinInt32 || number > math.MaxInt32 {
+			return state.CalculateRegisterTemporary(operation, typ, register, operand, number)
 		}
 
-		state.assembler.AddRegisterNumber(register, uint64(number))
+		switch {
+		case number == 1 && operation == "+":
+			state.assembler.IncreaseRegister(register)
 
-	case "-":
-		if number == 1 {
+		case number == 1 && operation == "-":
 			state.assembler.DecreaseRegister(register)
-			return nil
-		}
 
-		state.assembler.SubRegisterNumber(register, uint64(number))
+		case operation == "+":
+			state.assembler.AddRegisterNumber(register, uint64(number))
+
+		case operation == "-":
+			state.assembler.SubRegisterNumber(register, uint64(number))
+		}
 
 	case "*":
+		// The immediate value is sign-extended from 32 bits.
+		if number < math.MinInt32 || number > math.MaxInt32 {
+			return state.CalculateRegisterTemporary(operation, typ, register, operand, number)
+		}
+
 		state.assembler.MulRegisterNumber(register, uint64(number))
 
-	case "/":
-		temporary := state.registers.General.FindFree()
+	case "&", "|", "^":
+		// The immediate value is sign-extended from 32 bits.
+		if number < math.MinInt32 || number > math.MaxInt32 {
+			return state.CalculateRegisterTemporary(operation, typ, register, operand, number)
+		}
 
-		if temporary == nil {
-			return errors.New(errors.ExceededMaxVariables)
+		switch operation {
+		case "&":
+			state.assembler.AndRegisterNumber(register, uint64(number))
+
+		case "|":
+			state.assembler.OrRegisterNumber(register, uint64(number))
+
+		case "^":
+			state.assembler.XorRegisterNumber(register, uint64(number))
 		}
 
-		temporary.ForceUse(operand)
-		state.assembler.MoveRegisterNumber(temporary, uint64(number))
-		err := state.CalculateRegisterRegister("/", register, temporary)
+	case "<<":
+		state.assembler.ShiftLeftRegisterNumber(register, uint64(number))
 
-		if err != nil {
-			return err
+	case ">>":
+		if typ.IsUnsigned() {
+			state.assembler.ShiftRightLogicalRegisterNumber(register, uint64(number))
+			return nil
 		}
 
-		temporary.Free()
+		state.assembler.ShiftRightRegisterNumber(register, uint64(number))
+
+	case ">>>":
+		state.assembler.ShiftRightLogicalRegisterNumber(register, uint64(number))
+
+	case "/", "%":
+		return state.CalculateRegisterTemporary(operation, typ, register, operand, number)
 
 	default:
 		return errors.New(errors.NotImplemented)
@@ -296,8 +769,25 @@ func (state *State) CalculateRegisterNumber(operation string, register *register
 	return nil
 }
 
+// CalculateRegisterTemporary moves the number into a temporary register
+// and performs the operation on both registers.
+func (state *State) CalculateRegisterTemporary(operation string, typ *types.Type, register *register.Register, operand *expression.Expression, number int64) error {
+	temporary := state.TemporaryRegisterIn(state.registers.General)
+
+	if temporary == nil {
+		return errors.New(errors.ExceededMaxVariables)
+	}
+
+	temporary.ForceUse(operand)
+	state.assembler.MoveRegisterNumber(temporary, uint64(number))
+	err := state.CalculateRegisterRegister(operation, typ, register, temporary)
+	temporary.Free()
+	return err
+}
+
 // CalculateRegisterRegister performs an operation on two registers.
-func (state *State) CalculateRegisterRegister(operation string, registerTo *register.Register, registerFrom *register.Register) error {
+// Unsigned integers use logical right shifts and unsigned divisions.
+func (state *State) CalculateRegisterRegister(operation string, typ *types.Type, registerTo *register.Register, registerFrom *register.Register) error {
 	switch operation {
 	case "+":
 		state.assembler.AddRegisterRegister(registerTo, registerFrom)
@@ -308,32 +798,140 @@ func (state *State) CalculateRegisterRegister(operation string, registerTo *regi
 	case "*":
 		state.assembler.MulRegisterRegister(registerTo, registerFrom)
 
-	case "/":
-		rax := state.registers.All.ByName("rax")
-		rdx := state.registers.All.ByName("rdx")
+	case "&":
+		state.assembler.AndRegisterRegister(registerTo, registerFrom)
 
-		if rax != registerTo {
-			err := state.TryFreeRegister(rax)
+	case "|":
+		state.assembler.OrRegisterRegister(registerTo, registerFrom)
 
-			if err != nil {
-				return err
-			}
+	case "^":
+		state.assembler.XorRegisterRegister(registerTo, registerFrom)
+
+	case "<<", ">>", ">>>":
+		if operation == ">>" && typ.IsUnsigned() {
+			operation = ">>>"
+		}
+
+		return state.Shift(operation, registerTo, registerFrom)
+
+	case "/", "%":
+		return state.Divide(operation, registerTo, registerFrom, typ.IsUnsigned())
+
+	default:
+		return errors.New(errors.NotImplemented)
+	}
+
+	return nil
+}
+
+// Divide performs a division of two registers.
+// The quotient is stored for "/" and the remainder for "%".
+func (state *State) Divide(operation string, registerTo *register.Register, registerFrom *register.Register, unsigned bool) error {
+	rax := state.registers.All.ByName("rax")
+	rdx := state.registers.All.ByName("rdx")
+
+	// The divisor must not be overwritten by the dividend or its sign extension.
+	if registerFrom == rax || registerFrom == rdx {
+		temporary := state.TemporaryRegisterIn(state.registers.General)
+
+		if temporary == nil {
+			return errors.New(errors.ExceededMaxVariables)
+		}
+
+		temporary.ForceUse(registerFrom.User())
+		state.assembler.MoveRegisterRegister(temporary, registerFrom)
+		defer temporary.Free()
+		registerFrom = temporary
+	}
+
+	// Variables in rax and rdx are moved to other registers.
+	// Temporary values like the number of a system call are saved on the stack.
+	var saved register.List
+
+	for _, reg := range []*register.Register{rax, rdx} {
+		if reg == registerTo || reg.IsFree() {
+			continue
+		}
 
-			state.assembler.MoveRegisterRegister(rax, registerTo)
+		if _, isVariable := reg.User().(*Variable); !isVariable {
+			state.assembler.PushRegister(reg)
+			saved = append(saved, reg)
+			continue
 		}
 
-		err := state.TryFreeRegister(rdx)
+		err := state.TryFreeRegister(reg)
 
 		if err != nil {
 			return err
 		}
+	}
+
+	defer state.RestoreRegisters(saved)
+
+	if rax != registerTo {
+		state.assembler.MoveRegisterRegister(rax, registerTo)
+	}
 
+	// The upper half of the dividend in rdx is zero for unsigned divisions
+	// and filled with the sign bit for signed divisions.
+	if unsigned {
+		state.assembler.XorRegisterRegister(rdx, rdx)
+		state.assembler.UnsignedDivRegister(registerFrom)
+	} else {
 		state.assembler.SignExtendToDX(rax)
 		state.assembler.DivRegister(registerFrom)
+	}
+
+	if operation == "%" {
+		state.assembler.MoveRegisterRegister(registerTo, rdx)
+	} else if registerTo != rax {
 		state.assembler.MoveRegisterRegister(registerTo, rax)
+	}
 
-	default:
-		return errors.New(errors.NotImplemented)
+	return nil
+}
+
+// Shift shifts the bits of a register by the number stored in another register.
+// The bit count of a shift needs to be in rcx.
+func (state *State) Shift(operation string, registerTo *register.Register, registerFrom *register.Register) error {
+	rcx := state.registers.All.ByName("rcx")
+	destination := registerTo
+
+	if registerFrom != rcx {
+		if registerTo == rcx {
+			destination = state.FindFreeRegister()
+
+			if destination == nil {
+				return errors.New(errors.ExceededMaxVariables)
+			}
+
+			destination.ForceUse(registerTo.User())
+			state.assembler.MoveRegisterRegister(destination, registerTo)
+		} else {
+			err := state.TryFreeRegister(rcx)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		state.assembler.MoveRegisterRegister(rcx, registerFrom)
+	}
+
+	switch operation {
+	case "<<":
+		state.assembler.ShiftLeftRegisterRegister(destination, rcx)
+
+	case ">>":
+		state.assembler.ShiftRightRegisterRegister(destination, rcx)
+
+	case ">>>":
+		state.assembler.ShiftRightLogicalRegisterRegister(destination, rcx)
+	}
+
+	if destination != registerTo {
+		state.assembler.MoveRegisterRegister(registerTo, destination)
+		destination.Free()
 	}
 
 	return nil
@@ -345,7 +943,7 @@ func (state *State) TryFreeRegister(reg *register.Register) error {
 		return nil
 	}
 
-	freeRegister := state.registers.General.FindFree()
+	freeRegister := state.FindFreeRegister()
 
 	if freeRegister == nil {
 		return errors.New(errors.ExceededMaxVariables)
@@ -361,7 +959,7 @@ func (state *State) TryFreeRegister(reg *register.Register) error {
 	return variable.SetRegister(freeRegister)
 }
 
-// ResolveAccessors combines the children in the dot operator to a single function name.
+// ResolveAccessors resolves the package and field access of all dot operators in the expression.
 func (state *State) ResolveAccessors(root *expression.Expression) error {
 	for _, child := range root.Children {
 		err := state.ResolveAccessors(child)
@@ -374,24 +972,97 @@ func (state *State) ResolveAccessors(root *expression.Expression) error {
 	return state.ResolveAccessor(root)
 }
 
-// ResolveAccessor combines the children in the dot operator to a single function name.
+// ResolveAccessor distinguishes package access from field access and method calls.
+// Package function calls are combined to a single function name,
+// field access is kept as a dot operation that loads the field.
 func (state *State) ResolveAccessor(root *expression.Expression) error {
-	if root.Token.Text() != "." || !root.Children[1].IsFunctionCall {
+	if root.Token.Kind != token.Operator || root.Token.Text() != "." {
+		return nil
+	}
+
+	left := root.Children[0]
+	right := root.Children[1]
+
+	// Field access on a nested field
+	if !left.IsLeaf() {
+		if right.IsFunctionCall {
+			return state.ResolveMethod(root)
+		}
+
+		return nil
+	}
+
+	leftName := left.Token.Text()
+
+	// Field access on a variable
+	if state.scopes.Get(leftName) != nil {
+		if right.IsFunctionCall {
+			return state.ResolveMethod(root)
+		}
+
+		return nil
+	}
+
+	// Enum case
+	if state.EnumType(leftName) != nil {
+		if right.IsFunctionCall {
+			return errors.New(errors.NotImplemented)
+		}
+
 		return nil
 	}
 
-	pkg := root.Children[0]
-	pkgName := pkg.Token.Text()
-	imp := state.function.File.imports[pkgName]
+	imp := state.function.File.imports[leftName]
 
 	if imp == nil {
-		return errors.New(state.UnknownPackageError(pkgName))
+		if right.IsFunctionCall {
+			return errors.New(state.UnknownPackageError(leftName))
+		}
+
+		return errors.New(state.UnknownVariableError(leftName))
+	}
+
+	// Enum types of other packages like `sys.Color` are combined to a single type name.
+	if !right.IsFunctionCall {
+		typ := state.environment.Types[imp.Path+"."+right.Token.Text()]
+
+		if typ == nil || !typ.IsEnum() {
+			return errors.New(errors.NotImplemented)
+		}
 	}
 
 	atomic.AddInt32(&imp.Used, 1)
 	newName := append(unsafe.StringToBytes(imp.Path), '.')
-	newName = append(newName, root.Children[1].Token.Bytes...)
-	root.Children[1].Token.Bytes = newName
-	root.Replace(root.Children[1])
+	newName = append(newName, right.Token.Bytes...)
+	right.Token.Bytes = newName
+	root.Replace(right)
+	return nil
+}
+
+// ResolveMethod turns a method call like `p.length()` into a call of the function `Point.length`.
+// The struct is passed as the first parameter of the call.
+func (state *State) ResolveMethod(root *expression.Expression) error {
+	receiver := root.Children[0]
+	call := root.Children[1]
+	typ := state.ExpressionType(receiver)
+
+	if typ == nil {
+		return errors.New(&errors.CantInferType{Expression: receiver.String()})
+	}
+
+	methodName := typ.Name + "." + call.Token.Text()
+
+	if len(state.environment.Overloads[methodName]) == 0 {
+		return errors.New(&errors.UnknownMethod{TypeName: typ.Name, Name: call.Token.Text()})
+	}
+
+	call.Token.Bytes = []byte(methodName)
+	call.Children = append([]*expression.Expression{receiver}, call.Children...)
+	root.Replace(call)
+
+	for _, child := range root.Children {
+		child.Parent = root
+	}
+
 	return nil
 }
@@ -6,29 +6,29 @@ import (
 	"sync"
 
 	"github.com/akyoto/directory"
-	"github.com/akyoto/q/build/types"
 )
 
 // FindFunctions scans the directory for functions.
-func FindFunctions(dir string, env *Environment) (<-chan *Function, <-chan *types.Type, <-chan *Import, <-chan error) {
+func FindFunctions(dir string, env *Environment) (<-chan *Function, <-chan *Struct, <-chan *Constant, <-chan *Import, <-chan error) {
 	functions := make(chan *Function, 16)
-	structs := make(chan *types.Type)
+	structs := make(chan *Struct)
+	constants := make(chan *Constant)
 	imports := make(chan *Import)
 	errors := make(chan error)
 
 	go func() {
-		findFunctions(dir, env, functions, structs, imports, errors)
+		findFunctions(dir, env, functions, structs, constants, imports, errors)
 
 		close(functions)
 		close(imports)
 		close(errors)
 	}()
 
-	return functions, structs, imports, errors
+	return functions, structs, constants, imports, errors
 }
 
 // findFunctions scans the directory for functions without channel allocations.
-func findFunctions(dir string, env *Environment, functions chan<- *Function, structs chan<- *types.Type, imports chan<- *Import, errors chan<- error) {
+func findFunctions(dir string, env *Environment, functions chan<- *Function, structs chan<- *Struct, constants chan<- *Constant, imports chan<- *Import, errors chan<- error) {
 	wg := sync.WaitGroup{}
 
 	directory.Walk(dir, func(name string) {
@@ -41,7 +41,7 @@ func findFunctions(dir string, env *Environment, functions chan<- *Function, str
 
 		go func() {
 			defer wg.Done()
-			findFunctionsInFile(fullPath, env, functions, structs, imports, errors)
+			findFunctionsInFile(fullPath, env, functions, structs, constants, imports, errors)
 		}()
 	})
 
@@ -49,25 +49,26 @@ func findFunctions(dir string, env *Environment, functions chan<- *Function, str
 }
 
 // FindFunctionsInFile a single file for functions.
-func FindFunctionsInFile(fileName string, env *Environment) (<-chan *Function, <-chan *types.Type, <-chan *Import, <-chan error) {
+func FindFunctionsInFile(fileName string, env *Environment) (<-chan *Function, <-chan *Struct, <-chan *Constant, <-chan *Import, <-chan error) {
 	functions := make(chan *Function, 16)
-	structs := make(chan *types.Type)
+	structs := make(chan *Struct)
+	constants := make(chan *Constant)
 	imports := make(chan *Import)
 	errors := make(chan error)
 
 	go func() {
-		findFunctionsInFile(fileName, env, functions, structs, imports, errors)
+		findFunctionsInFile(fileName, env, functions, structs, constants, imports, errors)
 
 		close(functions)
 		close(imports)
 		close(errors)
 	}()
 
-	return functions, structs, imports, errors
+	return functions, structs, constants, imports, errors
 }
 
 // findFunctionsInFile scans the file for functions without channel allocations.
-func findFunctionsInFile(fileName string, env *Environment, functions chan<- *Function, structs chan<- *types.Type, imports chan<- *Import, errors chan<- error) {
+func findFunctionsInFile(fileName string, env *Environment, functions chan<- *Function, structs chan<- *Struct, constants chan<- *Constant, imports chan<- *Import, errors chan<- error) {
 	file := NewFile(fileName)
 	file.environment = env
 	err := file.Tokenize()
@@ -77,7 +78,7 @@ func findFunctionsInFile(fileName string, env *Environment, functions chan<- *Fu
 		return
 	}
 
-	err = file.Scan(imports, structs, functions)
+	err = file.Scan(imports, structs, constants, functions)
 
 	if err != nil {
 		errors <- err
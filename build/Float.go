@@ -0,0 +1,159 @@
+package build
+
+import (
+	"encoding/binary"
+	"math"
+	"strconv"
+
+	"github.com/akyoto/q/build/errors"
+	"github.com/akyoto/q/build/expression"
+	"github.com/akyoto/q/build/register"
+	"github.com/akyoto/q/build/token"
+	"github.com/akyoto/q/build/types"
+)
+
+// NumberType returns the type of a number literal.
+// Numbers with a decimal point are floating-point numbers.
+func NumberType(number token.Token) *types.Type {
+	for _, c := range number.Bytes {
+		if c == '.' {
+			return types.Float
+		}
+	}
+
+	return types.Int
+}
+
+// FloatNumberToRegister moves a number literal with the given floating-point type into the register.
+// There is no instruction to move a constant into a floating-point register,
+// therefore the number is stored in the data section and loaded from there.
+func (state *State) FloatNumberToRegister(number token.Token, typ *types.Type, register *register.Register) error {
+	value, err := state.ParseFloat(number.Text())
+
+	if err != nil {
+		return err
+	}
+
+	data := make([]byte, typ.Size)
+
+	if typ == types.Float32 {
+		binary.LittleEndian.PutUint32(data, math.Float32bits(float32(value)))
+	} else {
+		binary.LittleEndian.PutUint64(data, math.Float64bits(value))
+	}
+
+	address := state.assembler.AddString(string(data))
+	state.assembler.LoadFloatAddress(register, address, byte(typ.Size))
+	return nil
+}
+
+// CalculateFloatNumber performs an operation on a floating-point register and a number.
+func (state *State) CalculateFloatNumber(operation string, typ *types.Type, register *register.Register, operand *expression.Expression) error {
+	temporary := state.FindFreeRegisterFor(typ)
+
+	if temporary == nil {
+		return errors.New(errors.ExceededMaxVariables)
+	}
+
+	temporary.ForceUse(operand)
+	defer temporary.Free()
+	err := state.FloatNumberToRegister(operand.Token, typ, temporary)
+
+	if err != nil {
+		return err
+	}
+
+	return state.CalculateFloat(operation, typ, typ, register, temporary)
+}
+
+// CalculateFloat performs an operation on two floating-point registers.
+// Both operands need to have the same type, integers must be converted explicitly.
+func (state *State) CalculateFloat(operation string, typ *types.Type, operandType *types.Type, registerTo *register.Register, registerFrom *register.Register) error {
+	if operandType != typ {
+		return errors.New(&errors.InvalidType{Name: operandType.String(), Expected: typ.String()})
+	}
+
+	byteCount := byte(typ.Size)
+
+	switch operation {
+	case "+":
+		state.assembler.FloatAddRegisterRegister(registerTo, registerFrom, byteCount)
+
+	case "-":
+		state.assembler.FloatSubRegisterRegister(registerTo, registerFrom, byteCount)
+
+	case "*":
+		state.assembler.FloatMulRegisterRegister(registerTo, registerFrom, byteCount)
+
+	case "/":
+		state.assembler.FloatDivRegisterRegister(registerTo, registerFrom, byteCount)
+
+	default:
+		return errors.New(&errors.UnsupportedOperator{Operator: operation, Type: typ.Name})
+	}
+
+	return nil
+}
+
+// Convert converts the parameter of a type conversion like `Float(x)` or `Int(x)`.
+// Floating-point numbers are truncated when they're converted to integers
+// and integers wrap around when they're converted to a smaller integer type.
+func (state *State) Convert(conversion *expression.Expression, typ *types.Type) error {
+	parameter := conversion.Children[0]
+	temporary := state.FindFreeRegisterFor(state.ExpressionType(parameter))
+
+	if temporary == nil {
+		return errors.New(errors.ExceededMaxVariables)
+	}
+
+	temporary.ForceUse(parameter)
+	defer temporary.Free()
+	parameterType, err := state.ExpressionToRegister(parameter, temporary)
+
+	if err != nil {
+		return err
+	}
+
+	conversion.Type = typ
+
+	// The result of a conversion that isn't used doesn't need to be calculated.
+	if conversion.Register == nil {
+		return nil
+	}
+
+	switch {
+	case typ.IsFloat() && parameterType.IsFloat() && typ != parameterType:
+		state.assembler.FloatToFloat(conversion.Register, temporary, byte(typ.Size))
+
+	case typ.IsFloat() && !parameterType.IsFloat():
+		state.assembler.IntToFloat(conversion.Register, temporary, byte(typ.Size))
+
+	case !typ.IsFloat() && parameterType.IsFloat():
+		state.assembler.FloatToInt(conversion.Register, temporary, byte(parameterType.Size))
+
+	default:
+		state.assembler.MoveRegisterRegister(conversion.Register, temporary)
+	}
+
+	state.Wrap(conversion.Register, typ)
+	return nil
+}
+
+// ParseFloat parses a floating-point number.
+// Hexadecimal, binary and octal integers are converted to floating-point numbers.
+func (state *State) ParseFloat(numberString string) (float64, error) {
+	if _, base := IntegerDigits(numberString); base != 10 {
+		number, err := ParseInt(numberString)
+		return float64(number), err
+	}
+
+	number, err := strconv.ParseFloat(numberString, 64)
+
+	if err != nil {
+		return 0, errors.New(&errors.NotANumber{
+			Expression: numberString,
+		})
+	}
+
+	return number, nil
+}
@@ -17,16 +17,19 @@ type ForState struct {
 
 // ForLoop represents a for loop.
 type ForLoop struct {
-	labelStart    string
-	labelEnd      string
-	counter       *register.Register
-	limit         *register.Register
-	limitVariable *Variable
+	labelStart      string
+	labelNext       string
+	labelEnd        string
+	counter         *register.Register
+	counterVariable *Variable
+	limit           *register.Register
+	limitVariable   *Variable
 }
 
 // ForStart handles the start of for loops.
 func (state *State) ForStart(tokens []token.Token) error {
 	state.Skip(token.Keyword)
+	state.ReserveRegisters(2)
 	state.scopes.Push()
 	expression := tokens[1:]
 
@@ -38,6 +41,7 @@ func (state *State) ForStart(tokens []token.Token) error {
 
 	operatorPos := token.IndexKind(expression, token.Operator)
 	var register *register.Register
+	var counterVariable *Variable
 
 	if operatorPos == -1 {
 		start := expression[:rangePos]
@@ -46,7 +50,7 @@ func (state *State) ForStart(tokens []token.Token) error {
 			return errors.New(errors.MissingRangeStart)
 		}
 
-		register = state.registers.General.FindFree()
+		register = state.FindFreeRegister()
 
 		if register == nil {
 			return errors.New(errors.ExceededMaxVariables)
@@ -70,12 +74,21 @@ func (state *State) ForStart(tokens []token.Token) error {
 			return err
 		}
 
+		if variable.Type != types.Int {
+			return errors.New(&errors.InvalidType{Name: variable.Type.String(), Expected: types.Int.String()})
+		}
+
+		// The counter is modified at the end of each iteration,
+		// so it needs to stay alive until the loop ends.
+		variable.KeepAlive++
+		counterVariable = variable
 		register = variable.Register()
 	}
 
 	state.forState.counter++
 
 	labelStart := fmt.Sprintf("for_%d", state.forState.counter)
+	labelNext := fmt.Sprintf("for_%d_next", state.forState.counter)
 	labelEnd := fmt.Sprintf("for_%d_end", state.forState.counter)
 
 	upperLimit := expression[rangePos+1:]
@@ -85,17 +98,19 @@ func (state *State) ForStart(tokens []token.Token) error {
 	}
 
 	state.tokenCursor++
-	temporary, _, err := state.CompareRegisterExpression(register, upperLimit, labelStart)
+	temporary, _, err := state.CompareRegisterExpression(register, types.Int, upperLimit, labelStart)
 
 	if err != nil {
 		return err
 	}
 
 	forLoop := ForLoop{
-		labelStart: labelStart,
-		labelEnd:   labelEnd,
-		counter:    register,
-		limit:      temporary,
+		labelStart:      labelStart,
+		labelNext:       labelNext,
+		labelEnd:        labelEnd,
+		counter:         register,
+		counterVariable: counterVariable,
+		limit:           temporary,
 	}
 
 	// If we use an existing variable without a temporary register,
@@ -109,6 +124,7 @@ func (state *State) ForStart(tokens []token.Token) error {
 
 	state.assembler.JumpIfEqual(labelEnd)
 	state.forState.stack = append(state.forState.stack, forLoop)
+	state.PushBreakTarget(labelEnd, labelNext)
 	return nil
 }
 
@@ -122,12 +138,18 @@ func (state *State) ForEnd() error {
 
 	loop := state.forState.stack[len(state.forState.stack)-1]
 	state.forState.stack = state.forState.stack[:len(state.forState.stack)-1]
+	state.PopBreakTarget()
 
+	state.assembler.AddLabel(loop.labelNext)
 	state.assembler.IncreaseRegister(loop.counter)
 	state.assembler.Jump(loop.labelStart)
 	state.assembler.AddLabel(loop.labelEnd)
 	loop.counter.Free()
 
+	if loop.counterVariable != nil {
+		loop.counterVariable.KeepAlive--
+	}
+
 	if loop.limit != nil {
 		loop.limit.Free()
 	}
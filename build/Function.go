@@ -2,9 +2,11 @@ package build
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 
 	"github.com/akyoto/q/build/assembler"
+	"github.com/akyoto/q/build/assembler/instructions"
 	"github.com/akyoto/q/build/register"
 	"github.com/akyoto/q/build/token"
 	"github.com/akyoto/q/build/types"
@@ -23,6 +25,7 @@ type Function struct {
 	NoParameterCheck bool
 	IsBuiltin        bool
 	IsFinished       bool
+	IsMethod         bool
 	SideEffects      int32
 	CallCount        int32
 	Finished         *sync.Cond
@@ -30,6 +33,7 @@ type Function struct {
 	assembler        *assembler.Assembler
 	parameterStart   token.Position
 	returnTypeStart  token.Position
+	escapes          []bool
 }
 
 // Tokens returns all tokens within the function body (excluding the braces '{' and '}').
@@ -49,8 +53,20 @@ func (function *Function) NewError(position token.Position, err error) error {
 }
 
 // CanInline returns true if the function call can be inlined.
+// Functions that reference data like texts or floating-point numbers are never inlined,
+// because the addresses of the data are only valid in the function they were added to.
 func (function *Function) CanInline() bool {
-	return len(function.assembler.Instructions) <= 4
+	if len(function.assembler.Instructions) > 4 {
+		return false
+	}
+
+	for _, instr := range function.assembler.Instructions {
+		if _, isData := instr.(*instructions.RegisterAddress); isData {
+			return false
+		}
+	}
+
+	return true
 }
 
 // InlineInto adds the assembler instructions to another function.
@@ -62,6 +78,45 @@ func (function *Function) InlineInto(other *Function) {
 	other.assembler.Instructions = append(other.assembler.Instructions, inlinedInstructions...)
 }
 
+// ParameterRegisters returns the register of each parameter.
+// Parameters passed on the stack don't have a register.
+func (function *Function) ParameterRegisters(registers *register.Manager) register.List {
+	isFloat := make([]bool, len(function.Parameters))
+
+	for i, parameter := range function.Parameters {
+		isFloat[i] = parameter.Type.IsFloat()
+	}
+
+	return registers.ParameterRegisters(isFloat)
+}
+
+// ReturnValueRegisters returns the register of each return value.
+func (function *Function) ReturnValueRegisters(registers *register.Manager) register.List {
+	isFloat := make([]bool, len(function.ReturnTypes))
+
+	for i, typ := range function.ReturnTypes {
+		isFloat[i] = typ.IsFloat()
+	}
+
+	return registers.ReturnValueRegisters(isFloat)
+}
+
+// ReceiverTypeName returns the name of the struct a method like `Point.length` belongs to.
+func (function *Function) ReceiverTypeName() string {
+	name := UnpolymorphName(function.Name)
+	return name[:strings.LastIndexByte(name, '.')]
+}
+
+// PackagePrefix returns the package prefix like `sys.` of the function.
+// The struct name in the name of a method is not part of the prefix.
+func (function *Function) PackagePrefix() string {
+	if function.IsMethod {
+		return packagePrefix(function.ReceiverTypeName())
+	}
+
+	return packagePrefix(function.Name)
+}
+
 // HasReturnValue returns true if the function has a return value.
 func (function *Function) HasReturnValue() bool {
 	return len(function.ReturnTypes) > 0
@@ -113,3 +168,53 @@ func (function *Function) Wait() {
 func (function *Function) String() string {
 	return function.Name
 }
+
+// Signature returns the function name followed by its parameter types.
+func (function *Function) Signature() string {
+	typeNames := make([]string, 0, len(function.Parameters))
+
+	for _, parameter := range function.Parameters {
+		if parameter.Type == nil {
+			typeNames = append(typeNames, parameter.TypeName())
+			continue
+		}
+
+		typeNames = append(typeNames, parameter.Type.Name)
+	}
+
+	return fmt.Sprintf("%s(%s)", UnpolymorphName(function.Name), strings.Join(typeNames, ", "))
+}
+
+// Accepts returns true if the function can be called with arguments of the given types.
+// Arguments with an unknown type are accepted by every parameter.
+func (function *Function) Accepts(argumentTypes []*types.Type) bool {
+	if len(function.Parameters) != len(argumentTypes) {
+		return false
+	}
+
+	for i, parameter := range function.Parameters {
+		if argumentTypes[i] != nil && !argumentTypes[i].IsAssignableTo(parameter.Type) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// MatchScore returns the number of parameters that match the argument types.
+// It returns -1 if the number of arguments is different.
+func (function *Function) MatchScore(argumentTypes []*types.Type) int {
+	if len(function.Parameters) != len(argumentTypes) {
+		return -1
+	}
+
+	score := 0
+
+	for i, parameter := range function.Parameters {
+		if parameter.Type == argumentTypes[i] {
+			score++
+		}
+	}
+
+	return score
+}
@@ -3,113 +3,233 @@ package build
 import (
 	"fmt"
 
-	"github.com/akyoto/q/build/errors"
-	"github.com/akyoto/q/build/operators"
+	"github.com/akyoto/q/build/instruction"
 	"github.com/akyoto/q/build/token"
 )
 
 // IfState handles the state of branch compilation.
 type IfState struct {
 	counter int
-	labels  []string
+	stack   []IfBlock
+}
+
+// IfBlock represents an if block including all of its else branches.
+type IfBlock struct {
+	id          int
+	branch      int
+	labelNext   string
+	labelEnd    string
+	assignments map[*Variable]Assignment
+	merged      map[*Variable]Assignment
+}
+
+// Assignment saves the last assignment of a variable
+// so it can be restored at the start of each branch.
+type Assignment struct {
+	Position token.Position
+	Used     bool
 }
 
 // IfStart handles the start of if conditions.
 func (state *State) IfStart(tokens []token.Token) error {
 	state.Skip(token.Keyword)
-	state.scopes.Push()
+	state.ReserveRegisters(1)
 	condition := tokens[1:]
 
 	state.ifState.counter++
-	elseLabel := fmt.Sprintf("if_%d_end", state.ifState.counter)
-	state.ifState.labels = append(state.ifState.labels, elseLabel)
-
-	return state.Condition(condition, elseLabel)
-}
+	block := IfBlock{
+		id:       state.ifState.counter,
+		labelEnd: fmt.Sprintf("if_%d_end", state.ifState.counter),
+	}
 
-// Condition encodes a compare instruction for the given condition.
-func (state *State) Condition(condition []token.Token, elseLabel string) error {
-	operatorPos := -1
+	// The condition is evaluated on every code path,
+	// therefore it can still spill the variables of the outer scope.
+	block.labelNext = state.BranchLabel(&block)
+	err := state.Condition(condition, block.labelNext)
 
-	for i, t := range condition {
-		if t.Kind == token.Operator && operators.All[t.Text()].Kind == operators.Comparison {
-			operatorPos = i
-			break
-		}
+	if err != nil {
+		return err
 	}
 
-	if operatorPos == -1 {
-		return errors.New(errors.InvalidExpression)
+	state.scopes.Push()
+
+	if block.labelNext != block.labelEnd {
+		block.assignments = state.SaveAssignments()
+		block.merged = map[*Variable]Assignment{}
 	}
 
-	left := condition[:operatorPos]
-	leftRegister, leftType, err := state.EvaluateTokens(left)
+	state.ifState.stack = append(state.ifState.stack, block)
+	return nil
+}
+
+// ElseIfStart handles the start of chained if conditions.
+func (state *State) ElseIfStart(tokens []token.Token) error {
+	state.Skip(token.Keyword)
+	state.Skip(token.Keyword)
+	state.scopes.Push()
+	condition := tokens[2:]
+
+	block := &state.ifState.stack[len(state.ifState.stack)-1]
+	block.branch++
+	block.labelNext = state.BranchLabel(block)
+	state.RestoreAssignments(block.assignments)
+	err := state.Condition(condition, block.labelNext)
 
 	if err != nil {
 		return err
 	}
 
-	if leftType == nil {
-		return errors.New(&errors.CantInferType{Expression: fmt.Sprint(left)})
-	}
+	// Conditions are evaluated in every following branch.
+	block.assignments = state.SaveAssignments()
+	return nil
+}
 
-	right := condition[operatorPos+1:]
-	temporary, rightType, err := state.CompareRegisterExpression(leftRegister, right, "")
+// ElseStart handles the start of the alternative branch.
+func (state *State) ElseStart() error {
+	state.scopes.Push()
+	block := &state.ifState.stack[len(state.ifState.stack)-1]
+	state.RestoreAssignments(block.assignments)
+	return nil
+}
+
+// IfEnd handles the end of if conditions.
+// It is also used for the end of chained else if branches.
+func (state *State) IfEnd() error {
+	err := state.PopScope(false)
 
 	if err != nil {
 		return err
 	}
 
-	if rightType == nil {
-		return errors.New(&errors.CantInferType{Expression: fmt.Sprint(right)})
+	block := &state.ifState.stack[len(state.ifState.stack)-1]
+
+	if block.assignments == nil {
+		state.assembler.AddLabel(block.labelEnd)
+		state.ifState.stack = state.ifState.stack[:len(state.ifState.stack)-1]
+		return nil
 	}
 
-	if leftType != rightType {
-		return errors.New(&errors.InvalidType{Name: rightType.String(), Expected: leftType.String()})
+	state.MergeAssignments(block.assignments, block.merged)
+
+	if !state.NextIsElse() {
+		// Without a final else branch, the code after the
+		// block is reachable when every condition fails.
+		state.RestoreAssignments(block.assignments)
+		state.MergeAssignments(block.assignments, block.merged)
+		return state.closeIfBlock(block)
 	}
 
-	if temporary != nil {
-		temporary.Free()
+	state.assembler.Jump(block.labelEnd)
+	state.assembler.AddLabel(block.labelNext)
+	return nil
+}
+
+// ElseEnd handles the end of the alternative branch.
+func (state *State) ElseEnd() error {
+	err := state.PopScope(false)
+
+	if err != nil {
+		return err
 	}
 
-	operator := condition[operatorPos].Text()
-	state.IfFalseJump(operator, elseLabel)
+	block := &state.ifState.stack[len(state.ifState.stack)-1]
+	state.MergeAssignments(block.assignments, block.merged)
+	return state.closeIfBlock(block)
+}
+
+// closeIfBlock adds the final label of the if block
+// and applies the merged assignment states of all branches.
+func (state *State) closeIfBlock(block *IfBlock) error {
+	state.assembler.AddLabel(block.labelEnd)
+	state.RestoreAssignments(block.merged)
+	state.ifState.stack = state.ifState.stack[:len(state.ifState.stack)-1]
 	return nil
 }
 
-// IfFalseJump jumps if the previous compare statement was false.
-func (state *State) IfFalseJump(operator string, label string) {
-	switch operator {
-	case ">=":
-		state.assembler.JumpIfLess(label)
+// BranchLabel returns the label that is used when the condition of the current branch fails.
+func (state *State) BranchLabel(block *IfBlock) string {
+	if !state.HasElseBranch() {
+		return block.labelEnd
+	}
 
-	case ">":
-		state.assembler.JumpIfLessOrEqual(label)
+	return fmt.Sprintf("if_%d_else_%d", block.id, block.branch+1)
+}
 
-	case "<=":
-		state.assembler.JumpIfGreater(label)
+// HasElseBranch returns true if the branch starting at the
+// current instruction is followed by an else branch.
+func (state *State) HasElseBranch() bool {
+	depth := 0
 
-	case "<":
-		state.assembler.JumpIfGreaterOrEqual(label)
+	for index := state.instrCursor + 1; index < len(state.instructions); index++ {
+		switch state.instructions[index].Kind {
+		case instruction.IfStart, instruction.ElseIfStart, instruction.ElseStart, instruction.ForStart, instruction.LoopStart:
+			depth++
 
-	case "==":
-		state.assembler.JumpIfNotEqual(label)
+		case instruction.IfEnd, instruction.ElseIfEnd, instruction.ElseEnd, instruction.ForEnd, instruction.LoopEnd:
+			depth--
+		}
 
-	case "!=":
-		state.assembler.JumpIfEqual(label)
+		if depth >= 0 {
+			continue
+		}
+
+		return state.isElse(index + 1)
 	}
+
+	return false
 }
 
-// IfEnd handles the end of if conditions.
-func (state *State) IfEnd() error {
-	err := state.PopScope(false)
+// NextIsElse returns true if the instruction following
+// the current instruction starts an else branch.
+func (state *State) NextIsElse() bool {
+	return state.isElse(state.instrCursor + 1)
+}
 
-	if err != nil {
-		return err
+// isElse returns true if the instruction at the given index starts an else branch.
+func (state *State) isElse(index instruction.Position) bool {
+	if index >= len(state.instructions) {
+		return false
 	}
 
-	label := state.ifState.labels[len(state.ifState.labels)-1]
-	state.ifState.labels = state.ifState.labels[:len(state.ifState.labels)-1]
-	state.assembler.AddLabel(label)
-	return nil
+	kind := state.instructions[index].Kind
+	return kind == instruction.ElseIfStart || kind == instruction.ElseStart
+}
+
+// SaveAssignments saves the assignment state of all variables in the current scopes.
+func (state *State) SaveAssignments() map[*Variable]Assignment {
+	assignments := map[*Variable]Assignment{}
+
+	state.scopes.Each(func(variable *Variable) {
+		assignments[variable] = Assignment{
+			Position: variable.LastAssign,
+			Used:     variable.LastAssignUsed,
+		}
+	})
+
+	return assignments
+}
+
+// RestoreAssignments restores the assignment state of the given variables.
+func (state *State) RestoreAssignments(assignments map[*Variable]Assignment) {
+	for variable, assignment := range assignments {
+		variable.LastAssign = assignment.Position
+		variable.LastAssignUsed = assignment.Used
+	}
+}
+
+// MergeAssignments combines the assignment state of a finished branch with the
+// previous branches. An assignment is only considered used if it was used in every branch.
+func (state *State) MergeAssignments(assignments map[*Variable]Assignment, merged map[*Variable]Assignment) {
+	for variable := range assignments {
+		previous, exists := merged[variable]
+
+		if exists && !previous.Used {
+			continue
+		}
+
+		merged[variable] = Assignment{
+			Position: variable.LastAssign,
+			Used:     variable.LastAssignUsed,
+		}
+	}
 }
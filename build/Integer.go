@@ -0,0 +1,160 @@
+package build
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/akyoto/q/build/errors"
+	"github.com/akyoto/q/build/expression"
+	"github.com/akyoto/q/build/register"
+	"github.com/akyoto/q/build/token"
+	"github.com/akyoto/q/build/types"
+)
+
+// AdaptNumber returns the expected type if the token is an integer literal
+// and the expected type is an integer type. Otherwise it returns the original type.
+// Integer literals need to fit into the range of their type.
+func AdaptNumber(value token.Token, typ *types.Type, expected *types.Type) (*types.Type, error) {
+	if value.Kind != token.Number || typ != types.Int {
+		return typ, nil
+	}
+
+	if expected.IsInteger() {
+		typ = expected
+	}
+
+	if !NumberFits(value.Text(), typ) {
+		return nil, errors.New(&errors.NumberOutOfRange{Number: value.Text(), Type: typ.Name})
+	}
+
+	return typ, nil
+}
+
+// NumberFits returns true if the integer literal is in the range of the integer type.
+func NumberFits(number string, typ *types.Type) bool {
+	bitSize := int(typ.Size * 8)
+	digits, base := IntegerDigits(number)
+
+	if typ.IsUnsigned() {
+		_, err := strconv.ParseUint(digits, base, bitSize)
+		return err == nil
+	}
+
+	_, err := strconv.ParseInt(digits, base, bitSize)
+	return err == nil
+}
+
+// IntegerDigits returns the digits of an integer literal with its sign and the base of the number.
+// The prefixes 0x, 0b and 0o and the underscores between digits are removed.
+// Invalid literals are returned unchanged so that they fail to parse.
+func IntegerDigits(number string) (string, int) {
+	sign := ""
+	digits := number
+	base := 10
+
+	if strings.HasPrefix(digits, "-") {
+		sign = "-"
+		digits = digits[1:]
+	}
+
+	if len(digits) > 2 && digits[0] == '0' {
+		switch digits[1] {
+		case 'x':
+			base = 16
+
+		case 'b':
+			base = 2
+
+		case 'o':
+			base = 8
+		}
+
+		if base != 10 {
+			digits = digits[2:]
+		}
+	}
+
+	if !strings.Contains(digits, "_") {
+		return sign + digits, base
+	}
+
+	// Underscores are only allowed between two digits
+	if strings.HasPrefix(digits, "_") || strings.HasSuffix(digits, "_") || strings.Contains(digits, "__") {
+		return number, 10
+	}
+
+	return sign + strings.ReplaceAll(digits, "_", ""), base
+}
+
+// Wrap discards the bits of the register that don't fit into the integer type.
+// Values of sized integer types are always kept sign-extended (or zero-extended if they're unsigned)
+// to the full register so that they can be compared and calculated with 64-bit instructions.
+func (state *State) Wrap(register *register.Register, typ *types.Type) {
+	if !typ.IsInteger() || typ.Size >= 8 {
+		return
+	}
+
+	if typ.IsUnsigned() {
+		state.assembler.ZeroExtend(register, register, byte(typ.Size))
+		return
+	}
+
+	state.assembler.SignExtend(register, register, byte(typ.Size))
+}
+
+// Load loads a value of the given type from the memory address in the base register plus the offset.
+// Unsigned integers are zero-extended, all other values are sign-extended.
+func (state *State) Load(register *register.Register, base *register.Register, offset int32, typ *types.Type) {
+	if typ.IsUnsigned() {
+		state.assembler.LoadRegisterUnsigned(register, base, offset, byte(typ.Size))
+		return
+	}
+
+	state.assembler.LoadRegister(register, base, offset, byte(typ.Size))
+}
+
+// CalculateInteger performs an operation on two integer registers.
+// Both operands need to have the same type, except for the bit count of shifts.
+func (state *State) CalculateInteger(operation string, typ *types.Type, operandType *types.Type, registerTo *register.Register, registerFrom *register.Register) error {
+	if operandType != typ && !isShift(operation) {
+		return errors.New(&errors.InvalidType{Name: operandType.String(), Expected: typ.String()})
+	}
+
+	state.PrepareShift(operation, registerTo, typ)
+	err := state.CalculateRegisterRegister(operation, typ, registerTo, registerFrom)
+
+	if err != nil {
+		return err
+	}
+
+	state.Wrap(registerTo, typ)
+	return nil
+}
+
+// CalculateIntegerNumber performs an operation on an integer register and a number.
+func (state *State) CalculateIntegerNumber(operation string, typ *types.Type, register *register.Register, operand *expression.Expression) error {
+	state.PrepareShift(operation, register, typ)
+	err := state.CalculateRegisterNumber(operation, typ, register, operand)
+
+	if err != nil {
+		return err
+	}
+
+	state.Wrap(register, typ)
+	return nil
+}
+
+// PrepareShift clears the upper bits of sized integers before a logical right shift
+// so that only the bits of the type are shifted into the result.
+func (state *State) PrepareShift(operation string, register *register.Register, typ *types.Type) {
+	if operation != ">>>" || !typ.IsInteger() || typ.Size >= 8 {
+		return
+	}
+
+	state.assembler.ZeroExtend(register, register, byte(typ.Size))
+}
+
+// isShift returns true if the operation shifts the bits of the left operand.
+func isShift(operation string) bool {
+	return operation == "<<" || operation == ">>" || operation == ">>>"
+}
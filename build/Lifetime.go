@@ -1,32 +1,113 @@
 package build
 
 import (
+	"github.com/akyoto/q/build/instruction"
 	"github.com/akyoto/q/build/token"
 )
 
 // IdentifierLifeTimeMap returns a map of variable names
 // mapped to the position they were last used.
+// Values inside of interpolated texts like `"{x}"` count as a use of the text token.
 func IdentifierLifeTimeMap(tokens []token.Token) map[string]token.Position {
 	identifiers := map[string]token.Position{}
 
 	for i := len(tokens) - 1; i >= 0; i-- {
 		t := tokens[i]
 
-		if t.Kind != token.Identifier {
-			continue
+		switch t.Kind {
+		case token.Identifier:
+			addLifeTime(identifiers, t.Text(), i)
+
+		case token.Text:
+			for _, identifier := range InterpolatedIdentifiers(t.Text()) {
+				addLifeTime(identifiers, identifier, i)
+			}
+		}
+	}
+
+	return identifiers
+}
+
+// ExtendLoopLifeTimes extends the lifetime of variables used inside of a loop until the end of the loop,
+// because the loop body can be executed again after their last use.
+// Variables declared inside of the loop get a new value in every iteration and keep their lifetime.
+func ExtendLoopLifeTimes(identifiers map[string]token.Position, tokens []token.Token, instructions []instruction.Instruction, parameters []*Parameter) {
+	firstUse := map[string]token.Position{}
+
+	for _, parameter := range parameters {
+		firstUse[parameter.Name] = -1
+	}
+
+	for i, t := range tokens {
+		switch t.Kind {
+		case token.Identifier:
+			addLifeTime(firstUse, t.Text(), i)
+
+		case token.Text:
+			for _, identifier := range InterpolatedIdentifiers(t.Text()) {
+				addLifeTime(firstUse, identifier, i)
+			}
+		}
+	}
+
+	var loops []instruction.Instruction
+
+	for _, instr := range instructions {
+		switch instr.Kind {
+		case instruction.ForStart, instruction.LoopStart:
+			loops = append(loops, instr)
+
+		case instruction.ForEnd, instruction.LoopEnd:
+			if len(loops) == 0 {
+				continue
+			}
+
+			start := loops[len(loops)-1].Position
+			end := instr.Position + len(instr.Tokens)
+			loops = loops[:len(loops)-1]
+
+			for identifier, lastUse := range identifiers {
+				if lastUse >= start && lastUse < end && firstUse[identifier] < start {
+					identifiers[identifier] = end
+				}
+			}
 		}
+	}
+}
+
+// addLifeTime records the position of an identifier unless a position has already been recorded.
+func addLifeTime(identifiers map[string]token.Position, identifier string, position token.Position) {
+	_, exists := identifiers[identifier]
 
-		identifier := t.Text()
-		_, exists := identifiers[identifier]
+	if exists {
+		return
+	}
 
-		if exists {
-			continue
+	identifiers[identifier] = position
+}
+
+// UseCount returns how often the identifier is used by the token.
+// Text tokens can use identifiers inside of interpolated values.
+func UseCount(t token.Token, identifier string) int {
+	switch t.Kind {
+	case token.Identifier:
+		if t.Text() == identifier {
+			return 1
+		}
+
+	case token.Text:
+		count := 0
+
+		for _, interpolated := range InterpolatedIdentifiers(t.Text()) {
+			if interpolated == identifier {
+				count++
+			}
 		}
 
-		identifiers[identifier] = i
+		return count
 	}
 
-	return identifiers
+	return 0
 }
 
 // KillVariables frees the registers of all variables that die in the given token range.
@@ -41,7 +122,10 @@ func (state *State) KillVariables(from int, until int) {
 		}
 
 		// fmt.Println(variable, "died at", state.tokens[:variable.AliveUntil+1])
-		variable.Register().Free()
+		if !variable.IsSpilled() {
+			variable.Register().Free()
+		}
+
 		delete(state.identifierLifeTime, variable.Name)
 	})
 }
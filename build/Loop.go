@@ -1,20 +1,66 @@
 package build
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/akyoto/q/build/token"
+)
 
 // LoopState handles the state of loop compilation.
 type LoopState struct {
 	counter int
-	labels  []string
+	stack   []Loop
+}
+
+// Loop represents a loop that is currently being compiled.
+type Loop struct {
+	label     string
+	variables []*Variable
 }
 
 // LoopStart handles the start of loops.
-func (state *State) LoopStart() error {
+// A loop with a condition is repeated as long as the condition is true.
+func (state *State) LoopStart(tokens []token.Token) error {
+	state.Skip(token.Keyword)
+	state.ReserveRegisters(1)
 	state.scopes.Push()
 	state.loopState.counter++
 	label := fmt.Sprintf("loop_%d", state.loopState.counter)
-	state.loopState.labels = append(state.loopState.labels, label)
 	state.assembler.AddLabel(label)
+
+	loop := Loop{
+		label: label,
+	}
+
+	condition := tokens[1:]
+
+	if len(condition) > 0 {
+		err := state.Condition(condition, label+"_end")
+
+		if err != nil {
+			return err
+		}
+
+		// The condition is checked again in every iteration,
+		// so its variables need to stay alive until the loop ends.
+		for _, t := range condition {
+			if t.Kind != token.Identifier {
+				continue
+			}
+
+			variable := state.scopes.Get(t.Text())
+
+			if variable == nil {
+				continue
+			}
+
+			variable.KeepAlive++
+			loop.variables = append(loop.variables, variable)
+		}
+	}
+
+	state.loopState.stack = append(state.loopState.stack, loop)
+	state.PushBreakTarget(label+"_end", label)
 	return nil
 }
 
@@ -26,8 +72,19 @@ func (state *State) LoopEnd() error {
 		return err
 	}
 
-	label := state.loopState.labels[len(state.loopState.labels)-1]
-	state.assembler.Jump(label)
-	state.loopState.labels = state.loopState.labels[:len(state.loopState.labels)-1]
+	loop := state.loopState.stack[len(state.loopState.stack)-1]
+	state.assembler.Jump(loop.label)
+	state.assembler.AddLabel(loop.label + "_end")
+	state.loopState.stack = state.loopState.stack[:len(state.loopState.stack)-1]
+	state.PopBreakTarget()
+
+	for _, variable := range loop.variables {
+		variable.KeepAlive--
+
+		if variable.AliveUntil < state.tokenCursor {
+			variable.AliveUntil = state.tokenCursor
+		}
+	}
+
 	return nil
 }
@@ -0,0 +1,246 @@
+package build
+
+import (
+	"fmt"
+
+	"github.com/akyoto/q/build/errors"
+	"github.com/akyoto/q/build/expression"
+	"github.com/akyoto/q/build/register"
+	"github.com/akyoto/q/build/token"
+	"github.com/akyoto/q/build/types"
+)
+
+// MatchState handles the state of match compilation.
+type MatchState struct {
+	counter int
+	stack   []MatchBlock
+}
+
+// MatchBlock represents a match statement including all of its cases.
+type MatchBlock struct {
+	id          int
+	caseCount   int
+	value       *register.Register
+	typ         *types.Type
+	position    token.Position
+	labelNext   string
+	labelEnd    string
+	cases       map[string]bool
+	hasDefault  bool
+	assignments map[*Variable]Assignment
+	merged      map[*Variable]Assignment
+}
+
+// MatchStart handles the start of match statements.
+// The value is calculated once and compared with the values
+// of each case until one of them matches.
+func (state *State) MatchStart(tokens []token.Token) error {
+	position := state.tokenCursor
+	state.Skip(token.Keyword)
+	state.ReserveRegisters(2)
+	value := tokens[1:]
+
+	if len(value) == 0 {
+		return errors.New(errors.InvalidExpression)
+	}
+
+	valueRegister := state.FindFreeRegister()
+
+	if valueRegister == nil {
+		return errors.New(errors.ExceededMaxVariables)
+	}
+
+	valueRegister.ForceUse(token.List(value))
+	typ, err := state.TokensToRegister(value, valueRegister)
+
+	if err != nil {
+		valueRegister.Free()
+		return err
+	}
+
+	if typ == nil {
+		valueRegister.Free()
+		return errors.New(&errors.CantInferType{Expression: token.List(value).String()})
+	}
+
+	if !typ.IsInteger() && !typ.IsEnum() && typ != types.Bool {
+		valueRegister.Free()
+		return errors.New(&errors.InvalidType{Name: typ.String(), Expected: types.Int.String()})
+	}
+
+	state.matchState.counter++
+	state.matchState.stack = append(state.matchState.stack, MatchBlock{
+		id:          state.matchState.counter,
+		value:       valueRegister,
+		typ:         typ,
+		position:    position,
+		labelEnd:    fmt.Sprintf("match_%d_end", state.matchState.counter),
+		cases:       map[string]bool{},
+		assignments: state.SaveAssignments(),
+		merged:      map[*Variable]Assignment{},
+	})
+
+	return nil
+}
+
+// MatchEnd handles the end of match statements.
+// A match on an enum needs to handle every case unless it has a default case `_`.
+func (state *State) MatchEnd() error {
+	block := &state.matchState.stack[len(state.matchState.stack)-1]
+	state.matchState.stack = state.matchState.stack[:len(state.matchState.stack)-1]
+	block.value.Free()
+	isExhaustive := block.hasDefault
+
+	if block.typ.IsEnum() && !block.hasDefault {
+		for _, caseName := range block.typ.Cases {
+			name := block.typ.Name + "." + caseName
+
+			if !block.cases[name] {
+				state.tokenCursor = block.position
+				return errors.New(&errors.MissingCase{Name: name})
+			}
+		}
+
+		isExhaustive = true
+	}
+
+	// Without a matching case, the code after the match is reachable
+	// with the assignments from before the match.
+	if !isExhaustive {
+		state.RestoreAssignments(block.assignments)
+		state.MergeAssignments(block.assignments, block.merged)
+	}
+
+	state.assembler.AddLabel(block.labelEnd)
+	state.RestoreAssignments(block.merged)
+	return nil
+}
+
+// CaseStart handles the start of a case inside of a match statement.
+// A case can list multiple values separated by commas, `_` matches every value.
+func (state *State) CaseStart(tokens []token.Token) error {
+	block := &state.matchState.stack[len(state.matchState.stack)-1]
+	block.caseCount++
+	block.labelNext = fmt.Sprintf("match_%d_case_%d", block.id, block.caseCount)
+	labelBody := block.labelNext + "_body"
+	state.RestoreAssignments(block.assignments)
+
+	if len(tokens) == 1 && tokens[0].Kind == token.Identifier && tokens[0].Text() == "_" {
+		if block.hasDefault {
+			return errors.New(&errors.DuplicateCase{Name: "_"})
+		}
+
+		block.hasDefault = true
+		state.scopes.Push()
+		return nil
+	}
+
+	if block.hasDefault {
+		return errors.New(errors.UnreachableCase)
+	}
+
+	values := token.Split(tokens, token.Separator)
+
+	for index, value := range values {
+		err := state.CaseCompare(block, value)
+
+		if err != nil {
+			return err
+		}
+
+		if index == len(values)-1 {
+			state.assembler.JumpIfNotEqual(block.labelNext)
+		} else {
+			state.assembler.JumpIfEqual(labelBody)
+		}
+	}
+
+	if len(values) > 1 {
+		state.assembler.AddLabel(labelBody)
+	}
+
+	state.scopes.Push()
+	return nil
+}
+
+// CaseEnd handles the end of a case inside of a match statement.
+func (state *State) CaseEnd() error {
+	err := state.PopScope(false)
+
+	if err != nil {
+		return err
+	}
+
+	block := &state.matchState.stack[len(state.matchState.stack)-1]
+	state.MergeAssignments(block.assignments, block.merged)
+	state.assembler.Jump(block.labelEnd)
+	state.assembler.AddLabel(block.labelNext)
+	return nil
+}
+
+// CaseCompare compares the value of the match with a single value of a case.
+// Enum cases are compared with their number and every value can only be listed once.
+func (state *State) CaseCompare(block *MatchBlock, value []token.Token) error {
+	if len(value) == 0 {
+		return errors.New(errors.InvalidExpression)
+	}
+
+	expr, err := expression.FromTokens(value)
+
+	if err != nil {
+		return err
+	}
+
+	defer expr.Close()
+	err = state.ResolveAccessors(expr)
+
+	if err != nil {
+		return err
+	}
+
+	name := expr.String()
+	enumType, number, err := state.EnumValue(expr)
+
+	if err != nil {
+		return err
+	}
+
+	if enumType != nil {
+		name = enumType.Name + "." + enumType.Cases[number]
+	}
+
+	if block.cases[name] {
+		return errors.New(&errors.DuplicateCase{Name: name})
+	}
+
+	block.cases[name] = true
+
+	if enumType != nil {
+		if enumType != block.typ {
+			return errors.New(&errors.InvalidType{Name: enumType.String(), Expected: block.typ.String()})
+		}
+
+		state.assembler.CompareRegisterNumber(block.value, uint64(number))
+		return nil
+	}
+
+	temporary, typ, err := state.CompareRegisterExpression(block.value, block.typ, value, "")
+
+	if err != nil {
+		return err
+	}
+
+	if temporary != nil {
+		temporary.Free()
+	}
+
+	if typ == nil {
+		return errors.New(&errors.CantInferType{Expression: name})
+	}
+
+	if typ != block.typ {
+		return errors.New(&errors.InvalidType{Name: typ.String(), Expected: block.typ.String()})
+	}
+
+	return nil
+}
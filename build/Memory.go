@@ -0,0 +1,288 @@
+package build
+
+import (
+	"math"
+
+	"github.com/akyoto/q/build/errors"
+	"github.com/akyoto/q/build/expression"
+	"github.com/akyoto/q/build/register"
+	"github.com/akyoto/q/build/token"
+	"github.com/akyoto/q/build/types"
+)
+
+// LoadMemory executes a call to `load(ptr, offset, byteCount)`
+// and moves the value at the address `ptr + offset` into the register of the call.
+// Values smaller than 8 bytes are zero-extended, a conversion like `Int8(x)` interprets them as signed numbers.
+func (state *State) LoadMemory(call *expression.Expression) error {
+	byteCount, err := state.ByteCount(call.Children[2])
+
+	if err != nil {
+		return err
+	}
+
+	temporaries, err := state.OperandsToRegisters(call)
+
+	defer func() {
+		for _, temporary := range temporaries {
+			temporary.Free()
+		}
+	}()
+
+	if err != nil {
+		return err
+	}
+
+	// The address can be calculated in the register of the call
+	// because the loaded value replaces it anyway.
+	addressRegister := call.Register
+
+	if addressRegister != nil && addressRegister.IsFloat() {
+		addressRegister = nil
+	}
+
+	base, offset, err := state.MemoryAddress(call.Children[0], call.Children[1], addressRegister, &temporaries)
+
+	if err != nil {
+		return err
+	}
+
+	call.Type = types.Int
+
+	// The result of a load that isn't used doesn't need to be loaded.
+	if call.Register == nil {
+		return nil
+	}
+
+	state.assembler.LoadRegisterUnsigned(call.Register, base, offset, byteCount)
+	return nil
+}
+
+// StoreMemory executes a call to `store(ptr, offset, byteCount, value)`
+// and stores the lowest bytes of the value at the address `ptr + offset`.
+func (state *State) StoreMemory(call *expression.Expression) error {
+	byteCount, err := state.ByteCount(call.Children[2])
+
+	if err != nil {
+		return err
+	}
+
+	temporaries, err := state.OperandsToRegisters(call)
+
+	defer func() {
+		for _, temporary := range temporaries {
+			temporary.Free()
+		}
+	}()
+
+	if err != nil {
+		return err
+	}
+
+	base, offset, err := state.MemoryAddress(call.Children[0], call.Children[1], nil, &temporaries)
+
+	if err != nil {
+		return err
+	}
+
+	value := call.Children[3]
+
+	if value.IsLeaf() && value.Token.Kind == token.Number && NumberType(value.Token) == types.Int {
+		number, err := state.ParseInt(value.Token.Text())
+
+		if err != nil {
+			return err
+		}
+
+		if !NumberFitsBytes(number, byteCount) {
+			return errors.New(&errors.NumberOutOfRange{Number: value.Token.Text(), Type: unsignedTypes[byteCount].Name})
+		}
+
+		// 64-bit stores can only encode a sign-extended 32-bit number
+		if byteCount < 8 || (number >= math.MinInt32 && number <= math.MaxInt32) {
+			state.assembler.StoreNumber(base, offset, byteCount, uint64(number))
+			return nil
+		}
+	}
+
+	valueRegister, valueType, err := state.OperandRegister(value, &temporaries)
+
+	if err != nil {
+		return err
+	}
+
+	if valueType == nil {
+		return errors.New(&errors.CantInferType{Expression: value.String()})
+	}
+
+	if valueType.IsFloat() {
+		return errors.New(&errors.InvalidType{Name: valueType.String(), Expected: types.Int.String()})
+	}
+
+	state.assembler.StoreRegister(base, offset, byteCount, valueRegister)
+	return nil
+}
+
+// MemoryAddress returns the base register and the offset of the address `ptr + offset`.
+// Constant offsets are encoded in the instruction,
+// variable offsets are added to the pointer in the address register.
+// If the address register is nil, a temporary register is used instead.
+// Temporary registers are appended to the list and need to be freed by the caller.
+func (state *State) MemoryAddress(pointer *expression.Expression, offset *expression.Expression, addressRegister *register.Register, temporaries *[]*register.Register) (*register.Register, int32, error) {
+	pointerRegister, pointerType, err := state.OperandRegister(pointer, temporaries)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if pointerType == nil {
+		return nil, 0, errors.New(&errors.CantInferType{Expression: pointer.String()})
+	}
+
+	if pointerType != types.Pointer && !pointerType.IsPointer() {
+		return nil, 0, errors.New(&errors.InvalidType{Name: pointerType.String(), Expected: "Pointer"})
+	}
+
+	if offset.IsLeaf() && offset.Token.Kind == token.Number && NumberType(offset.Token) == types.Int {
+		number, err := state.ParseInt(offset.Token.Text())
+
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if number < math.MinInt32 || number > math.MaxInt32 {
+			return nil, 0, errors.New(&errors.NumberOutOfRange{Number: offset.Token.Text(), Type: types.Int32.Name})
+		}
+
+		return pointerRegister, int32(number), nil
+	}
+
+	offsetRegister, offsetType, err := state.OperandRegister(offset, temporaries)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if offsetType == nil {
+		return nil, 0, errors.New(&errors.CantInferType{Expression: offset.String()})
+	}
+
+	if !offsetType.IsInteger() {
+		return nil, 0, errors.New(&errors.InvalidType{Name: offsetType.String(), Expected: types.Int.String()})
+	}
+
+	// The temporary registers of calculated operands can hold the address as well.
+	if addressRegister == nil && !offset.IsLeaf() {
+		addressRegister = offsetRegister
+	}
+
+	if addressRegister == nil && !pointer.IsLeaf() {
+		addressRegister = pointerRegister
+	}
+
+	if addressRegister == nil {
+		addressRegister = state.FindFreeRegister()
+
+		if addressRegister == nil {
+			return nil, 0, errors.New(errors.ExceededMaxVariables)
+		}
+
+		addressRegister.ForceUse(pointer)
+		*temporaries = append(*temporaries, addressRegister)
+	}
+
+	// Addition is commutative, so the offset register can hold the address as well.
+	if addressRegister == offsetRegister {
+		state.assembler.AddRegisterRegister(addressRegister, pointerRegister)
+		return addressRegister, 0, nil
+	}
+
+	if addressRegister != pointerRegister {
+		state.assembler.MoveRegisterRegister(addressRegister, pointerRegister)
+	}
+
+	state.assembler.AddRegisterRegister(addressRegister, offsetRegister)
+	return addressRegister, 0, nil
+}
+
+// OperandRegister returns the register holding the value of a `load` or `store` parameter.
+// Parameters that are not simple operands need to be evaluated by OperandsToRegisters first.
+// Temporary registers are appended to the list and need to be freed by the caller.
+func (state *State) OperandRegister(operand *expression.Expression, temporaries *[]*register.Register) (*register.Register, *types.Type, error) {
+	if !operand.IsLeaf() {
+		return operand.Register, operand.Type, nil
+	}
+
+	if operand.Token.Kind == token.Identifier {
+		variableName := operand.Token.Text()
+		variable := state.scopes.Get(variableName)
+
+		if variable == nil {
+			return nil, nil, errors.New(state.UnknownVariableError(variableName))
+		}
+
+		state.UseVariable(variable)
+		variableRegister, err := state.VariableRegister(variable)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if variableRegister != variable.Register() {
+			*temporaries = append(*temporaries, variableRegister)
+		}
+
+		return variableRegister, variable.Type, nil
+	}
+
+	temporary := state.FindFreeRegister()
+
+	if temporary == nil {
+		return nil, nil, errors.New(errors.ExceededMaxVariables)
+	}
+
+	temporary.ForceUse(operand)
+	*temporaries = append(*temporaries, temporary)
+	typ, err := state.TokenToRegister(operand.Token, temporary)
+	return temporary, typ, err
+}
+
+// ByteCount returns the number of bytes of a memory access.
+// It needs to be a number literal so that the size of the access is known at compile time.
+func (state *State) ByteCount(operand *expression.Expression) (byte, error) {
+	if !operand.IsLeaf() || operand.Token.Kind != token.Number {
+		return 0, errors.New(&errors.InvalidByteCount{ByteCount: operand.String()})
+	}
+
+	number, err := state.ParseInt(operand.Token.Text())
+
+	if err != nil {
+		return 0, err
+	}
+
+	switch number {
+	case 1, 2, 4, 8:
+		return byte(number), nil
+
+	default:
+		return 0, errors.New(&errors.InvalidByteCount{ByteCount: operand.Token.Text()})
+	}
+}
+
+// unsignedTypes maps a byte count to the unsigned integer type of that size.
+var unsignedTypes = map[byte]*types.Type{
+	1: types.UInt8,
+	2: types.UInt16,
+	4: types.UInt32,
+	8: types.UInt64,
+}
+
+// NumberFitsBytes returns true if the number fits into the byte count
+// as either a signed or an unsigned integer.
+func NumberFitsBytes(number int64, byteCount byte) bool {
+	if byteCount >= 8 {
+		return true
+	}
+
+	bits := uint(byteCount) * 8
+	return number >= -(1<<(bits-1)) && number < 1<<bits
+}
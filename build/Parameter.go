@@ -18,3 +18,8 @@ type Parameter struct {
 func (parameter *Parameter) String() string {
 	return parameter.Name
 }
+
+// TypeName returns the name of the parameter type as written in the source code.
+func (parameter *Parameter) TypeName() string {
+	return token.List(parameter.TypeTokens).String()
+}
@@ -0,0 +1,284 @@
+package build
+
+import (
+	"math"
+	"math/bits"
+
+	"github.com/akyoto/q/build/errors"
+	"github.com/akyoto/q/build/expression"
+	"github.com/akyoto/q/build/register"
+	"github.com/akyoto/q/build/token"
+	"github.com/akyoto/q/build/types"
+)
+
+// DereferenceToRegister loads the value a pointer points to into the register of the `*` operation.
+// Structs and arrays are referred to by their address, therefore their pointers don't need to be loaded.
+func (state *State) DereferenceToRegister(dereference *expression.Expression) error {
+	pointer := dereference.Children[0]
+	pointerRegister := pointer.Register
+	pointerType := pointer.Type
+
+	if pointer.IsLeaf() {
+		if pointer.Token.Kind != token.Identifier {
+			return errors.New(errors.NotAPointer)
+		}
+
+		variableName := pointer.Token.Text()
+		variable := state.scopes.Get(variableName)
+
+		if variable == nil {
+			return errors.New(state.UnknownVariableError(variableName))
+		}
+
+		state.UseVariable(variable)
+		state.AddOperand(variable)
+		pointerRegister = variable.Register()
+		pointerType = variable.Type
+
+		if variable.IsSpilled() {
+			pointerRegister = pointer.Register
+			state.LoadSpilled(variable, pointerRegister)
+		}
+	}
+
+	if pointerType == nil || !pointerType.IsPointer() {
+		return errors.New(errors.NotAPointer)
+	}
+
+	element := pointerType.Element
+	dereference.Type = element
+
+	if element.IsArray() || len(element.Fields) > 0 {
+		if dereference.Register != pointerRegister {
+			state.assembler.MoveRegisterRegister(dereference.Register, pointerRegister)
+		}
+
+		return nil
+	}
+
+	state.Load(dereference.Register, pointerRegister, 0, element)
+	return nil
+}
+
+// AddressToRegister moves the address of a struct, a struct field or an array element
+// into the register of the `&` operation.
+func (state *State) AddressToRegister(address *expression.Expression) error {
+	operand := address.Children[0]
+
+	switch {
+	case operand.IsLeaf():
+		if operand.Token.Kind != token.Identifier {
+			return errors.New(errors.NotAddressable)
+		}
+
+		variableName := operand.Token.Text()
+		variable := state.scopes.Get(variableName)
+
+		if variable == nil {
+			return errors.New(state.UnknownVariableError(variableName))
+		}
+
+		// Only structs live in memory, other variables are stored in registers.
+		if len(variable.Type.Fields) == 0 {
+			return errors.New(errors.NotAddressable)
+		}
+
+		state.UseVariable(variable)
+
+		if variable.IsSpilled() {
+			state.LoadSpilled(variable, address.Register)
+		} else if variable.Register() != address.Register {
+			state.assembler.MoveRegisterRegister(address.Register, variable.Register())
+		}
+
+		address.Type = types.PointerTo(variable.Type)
+		return nil
+
+	case operand.Token.Text() == ".":
+		return state.FieldAddress(address, operand)
+
+	case operand.Token.Text() == "[":
+		return state.ElementAddressToRegister(address, operand)
+
+	default:
+		return errors.New(errors.NotAddressable)
+	}
+}
+
+// FieldAddress moves the address of a struct field into the register of the `&` operation.
+func (state *State) FieldAddress(address *expression.Expression, access *expression.Expression) error {
+	left := access.Children[0]
+	structType, err := state.ExpressionToRegister(left, address.Register)
+
+	if err != nil {
+		return err
+	}
+
+	if structType == nil {
+		return errors.New(&errors.CantInferType{Expression: left.String()})
+	}
+
+	fieldName := access.Children[1].Token.Text()
+	field := structType.FieldByName(fieldName)
+
+	if field == nil {
+		return errors.New(UnknownFieldError(fieldName, structType))
+	}
+
+	if field.Offset != 0 {
+		state.assembler.AddRegisterNumber(address.Register, uint64(field.Offset))
+	}
+
+	address.Type = types.PointerTo(field.Type)
+	return nil
+}
+
+// ElementAddressToRegister moves the address of an array element into the register of the `&` operation.
+func (state *State) ElementAddressToRegister(address *expression.Expression, access *expression.Expression) error {
+	temporaryRegisters, err := state.OperandsToRegisters(access)
+
+	defer func() {
+		for _, temporary := range temporaryRegisters {
+			temporary.Free()
+		}
+	}()
+
+	if err != nil {
+		return err
+	}
+
+	arrayRegister, arrayType, err := state.ArrayOperand(access.Children[0])
+
+	if err != nil {
+		return err
+	}
+
+	if _, isTemporary := arrayRegister.User().(spilledValue); isTemporary {
+		temporaryRegisters = append(temporaryRegisters, arrayRegister)
+	}
+
+	// The address can't be calculated in the register
+	// that still holds the address of the array.
+	addressRegister := address.Register
+
+	if addressRegister == arrayRegister {
+		addressRegister = state.FindFreeRegister()
+
+		if addressRegister == nil {
+			return errors.New(errors.ExceededMaxVariables)
+		}
+
+		addressRegister.ForceUse(access)
+		temporaryRegisters = append(temporaryRegisters, addressRegister)
+	}
+
+	baseRegister, offset, err := state.ElementAddress(access, arrayRegister, arrayType, addressRegister)
+
+	if err != nil {
+		return err
+	}
+
+	if baseRegister != address.Register {
+		state.assembler.MoveRegisterRegister(address.Register, baseRegister)
+	}
+
+	if offset != 0 {
+		state.assembler.AddRegisterNumber(address.Register, uint64(offset))
+	}
+
+	address.Type = types.PointerTo(arrayType.Element)
+	return nil
+}
+
+// CalculatePointer adds an integer to a pointer or subtracts it from the pointer.
+// The integer is scaled by the size of the element type.
+func (state *State) CalculatePointer(operation string, typ *types.Type, operandType *types.Type, registerTo *register.Register, registerFrom *register.Register) error {
+	if operandType != types.Int {
+		return errors.New(&errors.InvalidType{Name: operandType.String(), Expected: types.Int.String()})
+	}
+
+	if operation != "+" && operation != "-" {
+		return errors.New(&errors.UnsupportedOperator{Operator: operation, Type: typ.Name})
+	}
+
+	offset := registerFrom
+
+	if typ.Element.Size != 1 {
+		offset = state.FindFreeRegister()
+
+		if offset == nil {
+			return errors.New(errors.ExceededMaxVariables)
+		}
+
+		offset.ForceUse(registerFrom.User())
+		defer offset.Free()
+		state.assembler.MoveRegisterRegister(offset, registerFrom)
+		state.Scale(offset, typ.Element.Size)
+	}
+
+	if operation == "+" {
+		state.assembler.AddRegisterRegister(registerTo, offset)
+	} else {
+		state.assembler.SubRegisterRegister(registerTo, offset)
+	}
+
+	return nil
+}
+
+// CalculatePointerNumber adds a number to a pointer or subtracts it from the pointer.
+// The number is scaled by the size of the element type at compile time.
+func (state *State) CalculatePointerNumber(operation string, typ *types.Type, register *register.Register, operand *expression.Expression) error {
+	if operation != "+" && operation != "-" {
+		return errors.New(&errors.UnsupportedOperator{Operator: operation, Type: typ.Name})
+	}
+
+	number, err := state.ParseInt(operand.Token.Text())
+
+	if err != nil {
+		return err
+	}
+
+	offset := number * int64(typ.Element.Size)
+
+	// The immediate value is sign-extended from 32 bits.
+	if offset < math.MinInt32 || offset > math.MaxInt32 {
+		return state.CalculateRegisterTemporary(operation, types.Int, register, operand, offset)
+	}
+
+	if operation == "+" {
+		state.assembler.AddRegisterNumber(register, uint64(offset))
+	} else {
+		state.assembler.SubRegisterNumber(register, uint64(offset))
+	}
+
+	return nil
+}
+
+// Scale multiplies the register by the size of an element.
+// Powers of two are multiplied with a shift.
+func (state *State) Scale(register *register.Register, size uint) {
+	if size&(size-1) == 0 {
+		state.assembler.ShiftLeftRegisterNumber(register, uint64(bits.TrailingZeros(size)))
+		return
+	}
+
+	state.assembler.MulRegisterNumber(register, uint64(size))
+}
+
+// AddressType infers the pointer type of the `&` operation on the operand.
+// It returns nil if the operand doesn't have an address.
+func (state *State) AddressType(operand *expression.Expression) *types.Type {
+	typ := state.ExpressionType(operand)
+
+	if typ == nil {
+		return nil
+	}
+
+	switch {
+	case operand.IsLeaf() && len(typ.Fields) > 0, operand.Token.Text() == ".", operand.Token.Text() == "[":
+		return types.PointerTo(typ)
+
+	default:
+		return nil
+	}
+}
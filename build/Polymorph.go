@@ -1,41 +1,228 @@
 package build
 
 import (
-	"fmt"
+	"sort"
 	"strings"
-)
 
-// This flag controls whether parametric polymorphism is enabled or not.
-const PolymorphismEnabled = false
+	"github.com/akyoto/q/build/errors"
+	"github.com/akyoto/q/build/expression"
+	"github.com/akyoto/q/build/token"
+	"github.com/akyoto/q/build/types"
+)
 
-// PolymorphName attaches parameter-specific information to the function name.
-func PolymorphName(functionName string, parameterCount int) string {
-	if !PolymorphismEnabled {
+// PolymorphName attaches the parameter types to the function name.
+// Functions without parameters keep their original name.
+func PolymorphName(functionName string, parameterTypes []string) string {
+	if len(parameterTypes) == 0 {
 		return functionName
 	}
 
-	if parameterCount == 0 {
+	return functionName + "|" + strings.Join(parameterTypes, ",")
+}
+
+// UnpolymorphName removes parameter-specific information from the function name.
+func UnpolymorphName(functionName string) string {
+	index := strings.IndexByte(functionName, '|')
+
+	if index == -1 {
 		return functionName
 	}
 
-	if functionName == "syscall" || functionName == "print" {
-		return functionName
+	return functionName[:index]
+}
+
+// ResolveFunctions resolves the parameter and return types of all functions
+// and registers every overload under its polymorph name.
+// Return types are needed before compilation because callers
+// can infer the type of a call while the callee is still being compiled.
+func (env *Environment) ResolveFunctions() error {
+	for name, overloads := range env.Overloads {
+		for _, function := range overloads {
+			typeNames := make([]string, 0, len(function.Parameters))
+
+			for index, parameter := range function.Parameters {
+				typeName := parameter.TypeName()
+
+				// The receiver of a method has the full type name including the package.
+				if index == 0 && function.IsMethod {
+					typeName = function.ReceiverTypeName()
+				}
+
+				parameter.Type = env.Type(typeName)
+
+				// Unknown types are reported when the function is compiled.
+				if parameter.Type != nil {
+					typeName = parameter.Type.Name
+				}
+
+				typeNames = append(typeNames, typeName)
+			}
+
+			err := env.ResolveReturnTypes(function)
+
+			if err != nil {
+				return err
+			}
+
+			function.Name = PolymorphName(name, typeNames)
+
+			if env.Functions[function.Name] != nil {
+				err := errors.New(&errors.FunctionAlreadyExists{Name: function.Signature()})
+				return NewError(err, function.File.path, function.File.tokens[:function.TokenStart], function)
+			}
+
+			env.Functions[function.Name] = function
+		}
 	}
 
-	return fmt.Sprintf("%s|%d", functionName, parameterCount)
+	return nil
 }
 
-// UnpolymorphName removes parameter-specific information from the function name.
-func UnpolymorphName(functionName string) string {
-	if !PolymorphismEnabled {
-		return functionName
+// ResolveReturnTypes parses the return types of the function.
+// Multiple return types are listed in brackets: `-> (Int, Int)`.
+func (env *Environment) ResolveReturnTypes(function *Function) error {
+	typeTokens := function.ReturnTypeTokens
+	position := function.returnTypeStart
+
+	if len(typeTokens) == 0 {
+		return nil
+	}
+
+	if typeTokens[0].Kind == token.GroupStart && typeTokens[len(typeTokens)-1].Kind == token.GroupEnd {
+		typeTokens = typeTokens[1 : len(typeTokens)-1]
+		position++
 	}
 
-	index := strings.LastIndex(functionName, "|")
+	file := function.File
 
-	if index == -1 {
-		return functionName
+	for _, typeList := range token.Split(typeTokens, token.Separator) {
+		if len(typeList) == 0 {
+			return NewError(errors.New(errors.MissingReturnType), file.path, file.tokens[:position+1], function)
+		}
+
+		typeName := token.List(typeList).String()
+		typ := env.Type(typeName)
+
+		if typ == nil {
+			return NewError(errors.New(env.UnknownTypeError(typeName)), file.path, file.tokens[:position+1], function)
+		}
+
+		function.ReturnTypes = append(function.ReturnTypes, typ)
+		position += len(typeList) + 1
 	}
 
-	return functionName[:index]
+	return nil
+}
+
+// ResolveCall finds the function that is called by the expression.
+// Overloaded functions are distinguished by the number and the types of their parameters.
+// It returns nil if the function doesn't exist.
+func (state *State) ResolveCall(call *expression.Expression) (function *Function, isBuiltin bool, err error) {
+	functionName := call.Token.Text()
+	overloads := state.environment.Overloads[functionName]
+
+	switch len(overloads) {
+	case 0:
+		function = BuiltinFunctions[functionName]
+		return function, function != nil, nil
+
+	case 1:
+		// Parameter errors of functions that aren't overloaded
+		// are reported when the parameters are checked.
+		return overloads[0], false, nil
+	}
+
+	argumentTypes := make([]*types.Type, len(call.Children))
+
+	for i, argument := range call.Children {
+		argumentTypes[i] = state.ExpressionType(argument)
+	}
+
+	var matches []*Function
+
+	for _, overload := range overloads {
+		if overload.Accepts(argumentTypes) {
+			matches = append(matches, overload)
+		}
+	}
+
+	// Texts are also accepted as pointers,
+	// therefore overloads with exactly matching types are preferred.
+	if len(matches) > 1 {
+		matches = bestMatches(matches, argumentTypes)
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, false, errors.New(UnknownOverloadError(functionName, argumentTypes, overloads))
+
+	case 1:
+		return matches[0], false, nil
+
+	default:
+		candidates := make([]string, 0, len(matches))
+
+		for _, match := range matches {
+			candidates = append(candidates, match.Signature())
+		}
+
+		sort.Strings(candidates)
+		return nil, false, errors.New(&errors.AmbiguousCall{Name: functionName, Candidates: candidates})
+	}
+}
+
+// bestMatches returns the functions with the highest number of exactly matching parameter types.
+func bestMatches(functions []*Function, argumentTypes []*types.Type) []*Function {
+	bestScore := -1
+	var best []*Function
+
+	for _, function := range functions {
+		score := function.MatchScore(argumentTypes)
+
+		switch {
+		case score > bestScore:
+			bestScore = score
+			best = append(best[:0], function)
+
+		case score == bestScore:
+			best = append(best, function)
+		}
+	}
+
+	return best
+}
+
+// UnknownOverloadError produces an error for calls that don't match any overload
+// and suggests the overload with the most matching parameter types.
+func UnknownOverloadError(functionName string, argumentTypes []*types.Type, overloads []*Function) error {
+	arguments := make([]string, 0, len(argumentTypes))
+
+	for _, typ := range argumentTypes {
+		if typ == nil {
+			arguments = append(arguments, "?")
+			continue
+		}
+
+		arguments = append(arguments, typ.Name)
+	}
+
+	sorted := make([]*Function, len(overloads))
+	copy(sorted, overloads)
+
+	sort.SliceStable(sorted, func(a, b int) bool {
+		aScore := sorted[a].MatchScore(argumentTypes)
+		bScore := sorted[b].MatchScore(argumentTypes)
+
+		if aScore != bScore {
+			return aScore > bScore
+		}
+
+		return sorted[a].Signature() < sorted[b].Signature()
+	})
+
+	return &errors.UnknownOverload{
+		Name:        functionName,
+		Arguments:   strings.Join(arguments, ", "),
+		CorrectName: sorted[0].Signature(),
+	}
 }
@@ -0,0 +1,321 @@
+package build
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/akyoto/asm/syscall"
+	"github.com/akyoto/q/build/errors"
+	"github.com/akyoto/q/build/expression"
+	"github.com/akyoto/q/build/register"
+	"github.com/akyoto/q/build/token"
+	"github.com/akyoto/q/build/types"
+)
+
+// maxIntLength is the maximum number of characters of a formatted 64-bit integer.
+const maxIntLength = 20
+
+// PrintState handles the state of print calls.
+type PrintState struct {
+	counter int
+}
+
+// Print executes a call to `print` and writes the parameter followed by a newline to the console.
+// Text literals are written as they are. Interpolated texts, texts and integers
+// are formatted at runtime in a buffer on the stack and written with a single system call.
+func (state *State) Print(call *expression.Expression) error {
+	parameter := call.Children[0]
+	parts := []textPart{{value: parameter}}
+
+	if parameter.IsLeaf() && parameter.Token.Kind == token.Text {
+		var err error
+		parts, err = state.SplitInterpolation(parameter.Token.Text())
+
+		if err != nil {
+			return err
+		}
+
+		defer closeTextParts(parts)
+	}
+
+	if text, isLiteral := literalText(parts); isLiteral {
+		saved := state.SaveRegisters(state.printRegisters())
+		state.printLn(text)
+		state.RestoreRegisters(saved)
+		return nil
+	}
+
+	valueTypes, err := state.PushTextValues(parts)
+
+	if err != nil {
+		return err
+	}
+
+	saved := state.SaveRegisters(state.printRegisters())
+	state.printParts(parts, valueTypes, len(saved))
+	state.RestoreRegisters(saved)
+	state.assembler.AddRegisterNumber(state.registers.Stack, uint64(len(valueTypes)*8))
+	return nil
+}
+
+// PushTextValues evaluates the values of an interpolated text in order
+// and pushes them on the stack. It returns the types of the values.
+func (state *State) PushTextValues(parts []textPart) ([]*types.Type, error) {
+	var valueTypes []*types.Type
+
+	for _, part := range parts {
+		if part.value == nil {
+			continue
+		}
+
+		typ := state.ExpressionType(part.value)
+
+		if typ != nil && !isPrintable(typ) {
+			return nil, errors.New(&errors.InvalidType{Name: typ.String(), Expected: types.Text.String(), ParameterName: "text"})
+		}
+
+		value := state.FindFreeRegister()
+
+		if value == nil {
+			return nil, errors.New(errors.ExceededMaxVariables)
+		}
+
+		value.ForceUse(part.value)
+		typ, err := state.ExpressionToRegister(part.value, value)
+
+		if err != nil {
+			value.Free()
+			return nil, err
+		}
+
+		if !isPrintable(typ) {
+			value.Free()
+			return nil, errors.New(&errors.InvalidType{Name: typ.String(), Expected: types.Text.String(), ParameterName: "text"})
+		}
+
+		state.assembler.PushRegister(value)
+		value.Free()
+		valueTypes = append(valueTypes, typ)
+	}
+
+	return valueTypes, nil
+}
+
+// printParts formats the parts of a text followed by a newline in a buffer on the stack
+// and writes the buffer to the console. The values of the parts have been pushed on the stack
+// before the saved registers, which are restored afterwards.
+func (state *State) printParts(parts []textPart, valueTypes []*types.Type, savedCount int) {
+	rax := state.registers.All.ByName("rax")
+	rdx := state.registers.All.ByName("rdx")
+	rsi := state.registers.All.ByName("rsi")
+	rdi := state.registers.All.ByName("rdi")
+	r8 := state.registers.All.ByName("r8")
+	stack := state.registers.Stack
+
+	// r8 keeps the original stack pointer to access the values
+	// and to release the buffer after the system call.
+	state.assembler.MoveRegisterRegister(r8, stack)
+	valueOffset := func(index int) int32 {
+		return int32((savedCount + len(valueTypes) - 1 - index) * 8)
+	}
+
+	// The buffer size is known at compile time except for the length of texts.
+	size := 1
+	hasText := false
+
+	for _, part := range parts {
+		size += len(part.literal)
+	}
+
+	for _, typ := range valueTypes {
+		if typ == types.Text {
+			hasText = true
+			continue
+		}
+
+		size += maxIntLength
+	}
+
+	if hasText {
+		state.assembler.MoveRegisterNumber(rdx, uint64(size))
+
+		for i, typ := range valueTypes {
+			if typ != types.Text {
+				continue
+			}
+
+			state.assembler.LoadRegister(rax, r8, valueOffset(i), 8)
+			state.assembler.LoadRegister(rax, rax, -types.TextLengthSize, types.TextLengthSize)
+			state.assembler.AddRegisterRegister(rdx, rax)
+		}
+
+		state.assembler.SubRegisterRegister(stack, rdx)
+	} else {
+		state.assembler.SubRegisterNumber(stack, uint64(size))
+	}
+
+	// rdi is the cursor that the parts are written to
+	state.assembler.MoveRegisterRegister(rdi, stack)
+	index := 0
+
+	for _, part := range parts {
+		if part.value == nil {
+			state.writeLiteral(rdi, part.literal)
+			continue
+		}
+
+		state.assembler.LoadRegister(rax, r8, valueOffset(index), 8)
+
+		if valueTypes[index] == types.Text {
+			state.writeText(rdi, rax)
+		} else {
+			state.writeInt(rdi, rax, valueTypes[index].IsUnsigned())
+		}
+
+		index++
+	}
+
+	state.writeLiteral(rdi, "\n")
+	state.assembler.MoveRegisterRegister(rdx, rdi)
+	state.assembler.SubRegisterRegister(rdx, stack)
+	state.assembler.MoveRegisterRegister(rsi, stack)
+	state.assembler.MoveRegisterNumber(rax, uint64(syscall.Write))
+	state.assembler.MoveRegisterNumber(rdi, 1)
+	state.assembler.Syscall()
+	state.assembler.MoveRegisterRegister(stack, r8)
+}
+
+// writeLiteral stores the bytes of a literal at the cursor and advances the cursor.
+func (state *State) writeLiteral(cursor *register.Register, literal string) {
+	data := []byte(literal)
+	offset := 0
+
+	for len(data)-offset >= 4 {
+		state.assembler.StoreNumber(cursor, int32(offset), 4, uint64(binary.LittleEndian.Uint32(data[offset:])))
+		offset += 4
+	}
+
+	if len(data)-offset >= 2 {
+		state.assembler.StoreNumber(cursor, int32(offset), 2, uint64(binary.LittleEndian.Uint16(data[offset:])))
+		offset += 2
+	}
+
+	if len(data)-offset >= 1 {
+		state.assembler.StoreNumber(cursor, int32(offset), 1, uint64(data[offset]))
+		offset++
+	}
+
+	if offset > 0 {
+		state.assembler.AddRegisterNumber(cursor, uint64(offset))
+	}
+}
+
+// writeText copies the bytes of the text in the register to the cursor and advances the cursor.
+func (state *State) writeText(cursor *register.Register, text *register.Register) {
+	rcx := state.registers.All.ByName("rcx")
+	rdx := state.registers.All.ByName("rdx")
+
+	state.printState.counter++
+	loopLabel := fmt.Sprintf("print_%d_copy", state.printState.counter)
+	endLabel := fmt.Sprintf("print_%d_copy_end", state.printState.counter)
+
+	state.assembler.LoadRegister(rcx, text, -types.TextLengthSize, types.TextLengthSize)
+	state.assembler.AddLabel(loopLabel)
+	state.assembler.CompareRegisterNumber(rcx, 0)
+	state.assembler.JumpIfEqual(endLabel)
+	state.assembler.LoadRegister(rdx, text, 0, 1)
+	state.assembler.StoreRegister(cursor, 0, 1, rdx)
+	state.assembler.AddRegisterNumber(text, 1)
+	state.assembler.AddRegisterNumber(cursor, 1)
+	state.assembler.SubRegisterNumber(rcx, 1)
+	state.assembler.Jump(loopLabel)
+	state.assembler.AddLabel(endLabel)
+}
+
+// writeInt stores the decimal digits of the integer at the cursor and advances the cursor.
+// The number register needs to be rax because it is divided by 10 for every digit.
+// The digits are counted first so that they can be written from right to left.
+func (state *State) writeInt(cursor *register.Register, number *register.Register, unsigned bool) {
+	rcx := state.registers.All.ByName("rcx")
+	rdx := state.registers.All.ByName("rdx")
+	rsi := state.registers.All.ByName("rsi")
+
+	state.printState.counter++
+	countLabel := fmt.Sprintf("print_%d_count", state.printState.counter)
+	digitsLabel := fmt.Sprintf("print_%d_digits", state.printState.counter)
+
+	// Negative numbers are written as a minus sign followed by the digits of the absolute value.
+	if !unsigned {
+		state.assembler.CompareRegisterNumber(number, 0)
+		state.assembler.JumpIfGreaterOrEqual(countLabel)
+		state.assembler.StoreNumber(cursor, 0, 1, '-')
+		state.assembler.AddRegisterNumber(cursor, 1)
+		state.assembler.XorRegisterRegister(rsi, rsi)
+		state.assembler.SubRegisterRegister(rsi, number)
+		state.assembler.MoveRegisterRegister(number, rsi)
+	}
+
+	state.assembler.AddLabel(countLabel)
+	state.assembler.MoveRegisterRegister(rsi, number)
+	state.assembler.MoveRegisterNumber(rcx, 10)
+	countLoop := countLabel + "_loop"
+	state.assembler.AddLabel(countLoop)
+	state.assembler.XorRegisterRegister(rdx, rdx)
+	state.assembler.UnsignedDivRegister(rcx)
+	state.assembler.AddRegisterNumber(cursor, 1)
+	state.assembler.CompareRegisterNumber(number, 0)
+	state.assembler.JumpIfNotEqual(countLoop)
+
+	state.assembler.MoveRegisterRegister(number, rsi)
+	state.assembler.MoveRegisterRegister(rsi, cursor)
+	state.assembler.AddLabel(digitsLabel)
+	state.assembler.XorRegisterRegister(rdx, rdx)
+	state.assembler.UnsignedDivRegister(rcx)
+	state.assembler.AddRegisterNumber(rdx, '0')
+	state.assembler.SubRegisterNumber(rsi, 1)
+	state.assembler.StoreRegister(rsi, 0, 1, rdx)
+	state.assembler.CompareRegisterNumber(number, 0)
+	state.assembler.JumpIfNotEqual(digitsLabel)
+}
+
+// printRegisters returns the registers that are modified by printing.
+func (state *State) printRegisters() register.List {
+	return register.List{
+		state.registers.All.ByName("rax"),
+		state.registers.All.ByName("rcx"),
+		state.registers.All.ByName("rdx"),
+		state.registers.All.ByName("rsi"),
+		state.registers.All.ByName("rdi"),
+		state.registers.All.ByName("r8"),
+		state.registers.All.ByName("r11"),
+	}
+}
+
+// SaveRegisters pushes the registers of the list that are in use on the stack
+// and returns the saved registers.
+func (state *State) SaveRegisters(registers register.List) register.List {
+	var saved register.List
+
+	for _, reg := range registers {
+		if reg.IsFree() {
+			continue
+		}
+
+		state.assembler.PushRegister(reg)
+		saved = append(saved, reg)
+	}
+
+	return saved
+}
+
+// RestoreRegisters pops the saved registers from the stack in reverse order.
+func (state *State) RestoreRegisters(saved register.List) {
+	for i := len(saved) - 1; i >= 0; i-- {
+		state.assembler.PopRegister(saved[i])
+	}
+}
+
+// isPrintable returns true if values of the type can be printed at runtime.
+func isPrintable(typ *types.Type) bool {
+	return typ == types.Text || typ.IsInteger()
+}
@@ -2,7 +2,9 @@ package build
 
 import (
 	"github.com/akyoto/q/build/errors"
+	"github.com/akyoto/q/build/register"
 	"github.com/akyoto/q/build/token"
+	"github.com/akyoto/q/build/types"
 )
 
 // Return handles return statements.
@@ -15,15 +17,11 @@ func (state *State) Return(tokens []token.Token) error {
 			return errors.New(errors.ReturnWithoutFunctionType)
 		}
 
-		typ, err := state.TokensToRegister(expression, state.registers.ReturnValue[0])
+		err := state.ReturnValues(token.Split(expression, token.Separator))
 
 		if err != nil {
 			return err
 		}
-
-		if typ != state.function.ReturnTypes[0] {
-			return errors.New(&errors.InvalidType{Name: typ.String(), Expected: state.function.ReturnTypes[0].String()})
-		}
 	} else if len(state.function.ReturnTypes) > 0 {
 		return errors.New(&errors.MissingReturnValue{ReturnType: state.function.ReturnTypes[0].Name})
 	}
@@ -36,3 +34,115 @@ func (state *State) Return(tokens []token.Token) error {
 	state.assembler.Jump("return")
 	return nil
 }
+
+// ReturnValues moves the values of a return statement into the return value registers.
+func (state *State) ReturnValues(values [][]token.Token) error {
+	returnTypes := state.function.ReturnTypes
+
+	if len(values) != len(returnTypes) {
+		return errors.New(&errors.ReturnCount{
+			FunctionName:  UnpolymorphName(state.function.Name),
+			CountGiven:    len(values),
+			CountRequired: len(returnTypes),
+		})
+	}
+
+	returnValueRegisters := state.function.ReturnValueRegisters(state.registers)
+
+	if len(values) == 1 {
+		typ, err := state.TokensToRegister(values[0], returnValueRegisters[0])
+
+		if err != nil {
+			return err
+		}
+
+		if len(values[0]) == 1 {
+			typ, err = AdaptNumber(values[0][0], typ, returnTypes[0])
+
+			if err != nil {
+				return err
+			}
+		}
+
+		if typ != returnTypes[0] {
+			return errors.New(&errors.InvalidType{Name: typ.String(), Expected: returnTypes[0].String()})
+		}
+
+		return state.ReturnCopy(values[0], typ, returnValueRegisters[0])
+	}
+
+	// Calculating a value could overwrite the return value registers,
+	// therefore all values are calculated before they are moved.
+	temporaries := make([]*register.Register, 0, len(values))
+
+	defer func() {
+		for _, temporary := range temporaries {
+			temporary.Free()
+		}
+	}()
+
+	for i, value := range values {
+		if len(value) == 0 {
+			return errors.New(&errors.MissingReturnValue{ReturnType: returnTypes[i].Name})
+		}
+
+		temporary := state.FindFreeRegisterFor(returnTypes[i])
+
+		if temporary == nil {
+			return errors.New(errors.ExceededMaxVariables)
+		}
+
+		temporary.ForceUse(token.List(value))
+		temporaries = append(temporaries, temporary)
+		typ, err := state.TokensToRegister(value, temporary)
+
+		if err != nil {
+			return err
+		}
+
+		if len(value) == 1 {
+			typ, err = AdaptNumber(value[0], typ, returnTypes[i])
+
+			if err != nil {
+				return err
+			}
+		}
+
+		if typ != returnTypes[i] {
+			return errors.New(&errors.InvalidType{Name: typ.String(), Expected: returnTypes[i].String()})
+		}
+
+		err = state.ReturnCopy(value, typ, temporary)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	for i, temporary := range temporaries {
+		state.assembler.MoveRegisterRegister(returnValueRegisters[i], temporary)
+	}
+
+	return nil
+}
+
+// ReturnCopy replaces a returned struct by a copy on the heap
+// because the struct could be stored in the stack frame of the function or one of its callers.
+// Structs created by literals and function calls are already stored on the heap.
+func (state *State) ReturnCopy(value []token.Token, typ *types.Type, valueRegister *register.Register) error {
+	if (len(typ.Fields) == 0 && !typ.IsArray()) || IsAllocation(value) {
+		return nil
+	}
+
+	source := state.FindFreeRegister()
+
+	if source == nil {
+		return errors.New(errors.ExceededMaxVariables)
+	}
+
+	source.ForceUse(token.List(value))
+	defer source.Free()
+	state.assembler.MoveRegisterRegister(source, valueRegister)
+	state.Allocate(valueRegister, typ.Size)
+	return state.CopyMemory(valueRegister, 0, source, typ.Size)
+}
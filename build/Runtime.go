@@ -0,0 +1,70 @@
+package build
+
+import (
+	"sync"
+
+	"github.com/akyoto/q/build/assembler"
+	"github.com/akyoto/q/build/register"
+	"github.com/akyoto/q/build/types"
+)
+
+// RuntimeAllocate is the name of the runtime function that allocates heap memory.
+const RuntimeAllocate = "runtime.allocate"
+
+// brk is the number of the system call that moves the end of the heap.
+const brk = 12
+
+// NewAllocator creates the runtime function that allocates zero-initialized heap memory
+// for structs that outlive the function they were created in.
+// The size is passed in the first call register and the address is returned in the first return value register.
+// The memory is taken from the end of the heap by moving the program break,
+// therefore small structs don't occupy a whole page like they would with mmap.
+// New memory received from the kernel is always zero and allocations are aligned to 16 bytes.
+func NewAllocator(verbose bool) *Function {
+	registers := register.NewManager()
+	a := assembler.New(verbose)
+	size := registers.Call[1]
+	address := registers.Call[2]
+
+	a.AddLabel(RuntimeAllocate)
+	a.MoveRegisterRegister(size, registers.Call[0])
+
+	// Current end of the heap
+	a.MoveRegisterNumber(registers.Syscall[0], brk)
+	a.MoveRegisterNumber(registers.Syscall[1], 0)
+	a.Syscall()
+
+	// Align the address to 16 bytes
+	a.AddRegisterNumber(registers.ReturnValue[0], 15)
+	a.ShiftRightLogicalRegisterNumber(registers.ReturnValue[0], 4)
+	a.ShiftLeftRegisterNumber(registers.ReturnValue[0], 4)
+	a.MoveRegisterRegister(address, registers.ReturnValue[0])
+
+	// Move the end of the heap behind the allocation
+	a.MoveRegisterRegister(registers.Syscall[1], address)
+	a.AddRegisterRegister(registers.Syscall[1], size)
+	a.MoveRegisterNumber(registers.Syscall[0], brk)
+	a.Syscall()
+
+	a.MoveRegisterRegister(registers.ReturnValue[0], address)
+	a.Return()
+
+	// The system call modifies the remaining return value registers.
+	for _, reg := range registers.ReturnValue {
+		a.UseRegisterID(reg.ID)
+	}
+
+	function := &Function{
+		Name: RuntimeAllocate,
+		Parameters: []*Parameter{
+			{Name: "size", Type: types.Int},
+		},
+		ReturnTypes: []*types.Type{types.Pointer},
+		IsFinished:  true,
+		SideEffects: 1,
+		assembler:   a,
+	}
+
+	function.Finished = sync.NewCond(&function.FinishedMutex)
+	return function
+}
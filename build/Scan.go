@@ -7,7 +7,7 @@ import (
 )
 
 // Scan scans the input file.
-func (file *File) Scan(imports chan<- *Import, structs chan<- *types.Type, functions chan<- *Function) error {
+func (file *File) Scan(imports chan<- *Import, structs chan<- *Struct, constants chan<- *Constant, functions chan<- *Function) error {
 	var (
 		tokens                  = file.tokens
 		newlines                = 0
@@ -52,16 +52,65 @@ begin:
 			}
 
 			if t.Text() == "struct" {
+				var structure *Struct
+				var err error
+
+				structure, index, err = file.scanStruct(tokens, index)
+
+				if err != nil {
+					return err
+				}
+
+				structs <- structure
+				continue
+			}
+
+			if t.Text() == "enum" {
 				var typ *types.Type
 				var err error
 
-				typ, index, err = file.scanStruct(tokens, index)
+				typ, index, err = file.scanEnum(tokens, index)
+
+				if err != nil {
+					return err
+				}
+
+				structs <- &Struct{Type: typ, File: file}
+				continue
+			}
+
+			// Methods declared with `mut` like `mut Point.move()` can modify the struct.
+			if t.Text() == "mut" && index+1 < len(tokens) && tokens[index+1].Kind == token.Identifier {
+				var function *Function
+				var err error
+				start := index
+
+				function, index, err = file.scanFunction(tokens, index+1)
+
+				if err != nil {
+					return err
+				}
+
+				if !function.IsMethod {
+					return NewError(errors.New(errors.MutableFunction), file.path, tokens[:start+2], nil)
+				}
+
+				function.Parameters[0].Mutable = true
+				functions <- function
+				continue
+			}
+
+			if t.Text() == "const" {
+				var constant *Constant
+				var err error
+
+				constant, index, err = file.scanConstant(tokens, index)
 
 				if err != nil {
 					return err
 				}
 
-				structs <- typ
+				constants <- constant
 				continue
 			}
 
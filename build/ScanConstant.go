@@ -0,0 +1,42 @@
+package build
+
+import (
+	"github.com/akyoto/q/build/errors"
+	"github.com/akyoto/q/build/token"
+)
+
+// scanConstant scans a constant declaration like `const O_CREAT = 64`.
+func (file *File) scanConstant(tokens token.List, index token.Position) (*Constant, token.Position, error) {
+	index++
+	name := tokens[index]
+
+	if name.Kind != token.Identifier {
+		return nil, index, NewError(errors.New(errors.MissingConstantName), file.path, tokens[:index+1], nil)
+	}
+
+	constant := &Constant{
+		Name:        name.Text(),
+		File:        file,
+		declaration: tokens[:index+1],
+	}
+
+	index++
+
+	if tokens[index].Kind != token.Operator || tokens[index].Text() != "=" {
+		return nil, index, NewError(errors.New(errors.MissingAssignmentOperator), file.path, tokens[:index+1], nil)
+	}
+
+	index++
+	valueStart := index
+
+	for index < len(tokens) && tokens[index].Kind != token.NewLine {
+		index++
+	}
+
+	if index == valueStart {
+		return nil, index, NewError(errors.New(errors.MissingAssignmentExpression), file.path, tokens[:index], nil)
+	}
+
+	constant.tokens = tokens[valueStart:index]
+	return constant, index, nil
+}
@@ -0,0 +1,53 @@
+package build
+
+import (
+	"github.com/akyoto/q/build/errors"
+	"github.com/akyoto/q/build/token"
+	"github.com/akyoto/q/build/types"
+)
+
+// scanEnum scans an enumeration of named cases.
+// The cases are numbered in the order of their declaration.
+func (file *File) scanEnum(tokens token.List, index token.Position) (*types.Type, token.Position, error) {
+	var (
+		blockLevel = 0
+		typ        = &types.Type{Size: 8}
+	)
+
+	index++
+	name := tokens[index]
+
+	if name.Kind != token.Identifier {
+		return typ, index, NewError(errors.New(errors.MissingEnumName), file.path, tokens[:index+1], nil)
+	}
+
+	typ.Name = name.Text()
+	index++
+
+	for ; index < len(tokens); index++ {
+		t := tokens[index]
+
+		switch t.Kind {
+		case token.Identifier:
+			if typ.CaseIndex(t.Text()) != -1 {
+				return typ, index, NewError(errors.New(&errors.DuplicateCase{Name: t.Text()}), file.path, tokens[:index+1], nil)
+			}
+
+			typ.Cases = append(typ.Cases, t.Text())
+
+		case token.BlockStart:
+			blockLevel++
+
+		case token.BlockEnd:
+			blockLevel--
+
+			if blockLevel != 0 {
+				continue
+			}
+
+			return typ, index, nil
+		}
+	}
+
+	return nil, index, nil
+}
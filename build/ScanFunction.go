@@ -21,6 +21,15 @@ func (file *File) scanFunction(tokens token.List, index token.Position) (*Functi
 		return nil, index, NewError(errors.New(errors.InvalidFunctionName), file.path, tokens[:index+1], nil)
 	}
 
+	// Methods like `Point.length()` receive the struct as the implicit parameter `self`.
+	receiver := index
+	isMethod := index+2 < len(tokens) && tokens[index+1].Kind == token.Operator && tokens[index+1].Text() == "." && tokens[index+2].Kind == token.Identifier
+
+	if isMethod {
+		functionName += "." + tokens[index+2].Text()
+		index += 2
+	}
+
 	if index+1 >= len(tokens) || tokens[index+1].Kind != token.GroupStart {
 		return nil, index, NewError(errors.New(errors.ParameterOpeningBracket), file.path, tokens[:index+2], nil)
 	}
@@ -28,9 +37,19 @@ func (file *File) scanFunction(tokens token.List, index token.Position) (*Functi
 	function := &Function{
 		Name:           functionName,
 		File:           file,
+		IsMethod:       isMethod,
 		parameterStart: index + 2,
 	}
 
+	// Errors in the receiver type point to the struct name.
+	if isMethod {
+		function.Parameters = append(function.Parameters, &Parameter{
+			Name:       "self",
+			TypeTokens: tokens[receiver : receiver+1],
+			Position:   receiver - 1,
+		})
+	}
+
 	function.Finished = sync.NewCond(&function.FinishedMutex)
 
 	if functionName == "main" {
@@ -49,7 +68,8 @@ func (file *File) scanFunction(tokens token.List, index token.Position) (*Functi
 
 		switch t.Kind {
 		case token.BlockStart:
-			if groupLevel > 0 {
+			// Struct literals like `Point{x: 1}` can be passed as parameters.
+			if groupLevel > 0 && (function.TokenStart == 0 || tokens[index-1].Kind != token.Identifier) {
 				return function, index, NewError(errors.New(&errors.MissingCharacter{Character: ")"}), file.path, tokens[:index+1], function)
 			}
 
@@ -78,7 +98,6 @@ func (file *File) scanFunction(tokens token.List, index token.Position) (*Functi
 			}
 
 			function.TokenEnd = index
-			function.Name = PolymorphName(function.Name, len(function.Parameters))
 			return function, index, nil
 
 		case token.GroupStart:
@@ -91,29 +110,23 @@ func (file *File) scanFunction(tokens token.List, index token.Position) (*Functi
 				continue
 			}
 
-			if function.TokenStart != 0 {
+			// The brackets after the parameters group the return types
+			if function.TokenStart != 0 || function.returnTypeStart != 0 {
 				continue
 			}
 
 			if function.parameterStart < index {
-				parameter := tokens[function.parameterStart:index]
-				parameterName := parameter[0]
+				err := file.scanParameter(function, tokens, index)
 
-				if len(parameter) == 1 {
-					return function, index, NewError(errors.New(&errors.MissingType{Of: parameterName.Text()}), file.path, tokens[:function.parameterStart+1], function)
+				if err != nil {
+					return function, index, err
 				}
 
-				function.Parameters = append(function.Parameters, &Parameter{
-					Name:       parameterName.Text(),
-					TypeTokens: parameter[1:],
-					Position:   function.parameterStart,
-				})
-
 				function.parameterStart = -1
 			}
 
 		case token.Separator:
-			if function == nil || function.TokenStart != 0 || groupLevel != 1 {
+			if function == nil || function.TokenStart != 0 || function.returnTypeStart != 0 || groupLevel != 1 {
 				continue
 			}
 
@@ -121,19 +134,12 @@ func (file *File) scanFunction(tokens token.List, index token.Position) (*Functi
 				continue
 			}
 
-			parameter := tokens[function.parameterStart:index]
-			parameterName := parameter[0]
+			err := file.scanParameter(function, tokens, index)
 
-			if len(parameter) == 1 {
-				return function, index, NewError(errors.New(&errors.MissingType{Of: parameterName.Text()}), file.path, tokens[:function.parameterStart+1], function)
+			if err != nil {
+				return function, index, err
 			}
 
-			function.Parameters = append(function.Parameters, &Parameter{
-				Name:       parameterName.Text(),
-				TypeTokens: parameter[1:],
-				Position:   function.parameterStart,
-			})
-
 			function.parameterStart = index + 1
 
 		case token.Operator:
@@ -156,3 +162,35 @@ func (file *File) scanFunction(tokens token.List, index token.Position) (*Functi
 
 	return function, index, nil
 }
+
+// scanParameter adds the parameter that ends at the index to the function.
+// Parameters declared with `mut` like `mut p Point` can be modified inside the function.
+func (file *File) scanParameter(function *Function, tokens token.List, index token.Position) error {
+	start := function.parameterStart
+	mutable := tokens[start].Kind == token.Keyword && tokens[start].Text() == "mut"
+
+	if mutable {
+		start++
+	}
+
+	parameter := tokens[start:index]
+
+	if len(parameter) == 0 {
+		return NewError(errors.New(errors.MissingParameter), file.path, tokens[:start+1], function)
+	}
+
+	parameterName := parameter[0]
+
+	if len(parameter) == 1 {
+		return NewError(errors.New(&errors.MissingType{Of: parameterName.Text()}), file.path, tokens[:start+1], function)
+	}
+
+	function.Parameters = append(function.Parameters, &Parameter{
+		Name:       parameterName.Text(),
+		TypeTokens: parameter[1:],
+		Mutable:    mutable,
+		Position:   start,
+	})
+
+	return nil
+}
@@ -7,59 +7,68 @@ import (
 )
 
 // scanStruct scans a data structure.
-func (file *File) scanStruct(tokens token.List, index token.Position) (*types.Type, token.Position, error) {
+// The field types are resolved by ResolveTypes after all files have been scanned.
+// The `packed` attribute after the name removes the padding between the fields: `struct Header packed {}`.
+func (file *File) scanStruct(tokens token.List, index token.Position) (*Struct, token.Position, error) {
 	var (
-		blockLevel = 0
-		typ        = &types.Type{}
-		field      *types.Field
+		blockLevel    = 0
+		typ           = &types.Type{}
+		structure     = &Struct{Type: typ, File: file}
+		field         *structField
+		pointerPrefix string
 	)
 
 	index++
 	name := tokens[index]
 
 	if name.Kind != token.Identifier {
-		return typ, index, NewError(errors.New(errors.MissingStructName), file.path, tokens[:index+1], nil)
+		return structure, index, NewError(errors.New(errors.MissingStructName), file.path, tokens[:index+1], nil)
 	}
 
 	typ.Name = name.Text()
 	index++
 
+	if index < len(tokens) && tokens[index].Kind == token.Identifier && tokens[index].Text() == "packed" {
+		typ.Packed = true
+		index++
+	}
+
 	for ; index < len(tokens); index++ {
 		t := tokens[index]
 
 		switch t.Kind {
 		case token.Identifier:
 			if field == nil {
-				field = &types.Field{
-					Name: t.Text(),
+				field = &structField{
+					Field: &types.Field{Name: t.Text()},
 				}
 
 				continue
 			}
 
-			if field.Type == nil {
-				typeName := t.Text()
-				field.Type = file.environment.Types[typeName]
-
-				if field.Type == nil {
-					return typ, index, NewError(errors.New(&errors.UnknownType{Name: typeName}), file.path, tokens[:index], nil)
-				}
-
+			if field.typeName == "" {
+				field.typeName = pointerPrefix + t.Text()
+				field.declaration = tokens[:index]
+				pointerPrefix = ""
 				continue
 			}
 
+		case token.Operator:
+			// Pointer types like *Int consist of multiple tokens
+			if field != nil && field.typeName == "" && t.Text() == "*" {
+				pointerPrefix += "*"
+			}
+
 		case token.NewLine:
 			if field == nil {
 				continue
 			}
 
-			if field.Type == nil {
-				return typ, index, NewError(errors.New(&errors.MissingType{Of: field.Name}), file.path, tokens[:index], nil)
+			if field.typeName == "" {
+				return structure, index, NewError(errors.New(&errors.MissingType{Of: field.Name}), file.path, tokens[:index], nil)
 			}
 
-			field.Offset = typ.Size
-			typ.Fields = append(typ.Fields, field)
-			typ.Size += field.Type.Size
+			structure.fields = append(structure.fields, field)
 			field = nil
 
 		case token.BlockStart:
@@ -72,7 +81,7 @@ func (file *File) scanStruct(tokens token.List, index token.Position) (*types.Ty
 				continue
 			}
 
-			return typ, index, nil
+			return structure, index, nil
 		}
 	}
 
@@ -52,6 +52,11 @@ func (stack *ScopeStack) Each(callBack func(*Variable)) {
 	}
 }
 
+// Current returns the scope at the top of the stack.
+func (stack *ScopeStack) Current() Scope {
+	return stack.scopes[len(stack.scopes)-1]
+}
+
 // Push pushes a new scope to the top of the stack.
 func (stack *ScopeStack) Push() {
 	stack.scopes = append(stack.scopes, Scope{})
@@ -82,7 +87,7 @@ func (stack *ScopeStack) Errors(isLoop bool) []*ScopeError {
 			})
 		}
 
-		if variable.Mutable && variable.LastAssign == variable.Position {
+		if variable.Mutable && variable.LastAssign == variable.Position && !variable.Modified {
 			scopeErrors = append(scopeErrors, &ScopeError{
 				Position: variable.Position,
 				Err:      errors.New(&errors.UnmodifiedMutable{Name: variable.Name}),
@@ -0,0 +1,356 @@
+package build
+
+import (
+	"github.com/akyoto/q/build/errors"
+	"github.com/akyoto/q/build/register"
+	"github.com/akyoto/q/build/types"
+)
+
+// StackState handles the stack frame that holds spilled variables and structs.
+type StackState struct {
+	size     int32
+	locked   int
+	operands []*Variable
+	borrowed []borrowedRegister
+	windows  []borrowWindow
+}
+
+// borrowWindow is a section of code that can borrow registers.
+// It saves the state at the start of the section.
+type borrowWindow struct {
+	locked   int
+	borrowed int
+	operands int
+}
+
+// borrowedRegister is the register of a variable that holds a temporary value.
+// The variable is stored on the stack until the register is returned.
+type borrowedRegister struct {
+	variable *Variable
+	register *register.Register
+}
+
+// spilledValue is the user of a temporary register
+// that holds the value of a spilled variable.
+type spilledValue struct {
+	variable *Variable
+}
+
+// String returns the name of the spilled variable.
+func (value spilledValue) String() string {
+	return value.variable.Name
+}
+
+// FindFreeRegister returns a free general purpose register.
+// If all registers are occupied, a variable is spilled to the stack
+// to free its register. It returns nil if no variable can be spilled.
+func (state *State) FindFreeRegister() *register.Register {
+	return state.FindFreeRegisterIn(state.registers.General)
+}
+
+// FindFreeRegisterFor returns a free register that can hold a value of the given type.
+// Floating-point numbers are stored in the floating-point registers.
+func (state *State) FindFreeRegisterFor(typ *types.Type) *register.Register {
+	if typ.IsFloat() {
+		return state.FindFreeRegisterIn(state.registers.Float)
+	}
+
+	return state.FindFreeRegister()
+}
+
+// FindFreeRegisterIn returns a free register of the given list
+// and spills a variable stored in the list if all of them are occupied.
+func (state *State) FindFreeRegisterIn(registers register.List) *register.Register {
+	free := registers.FindFree()
+
+	if free != nil {
+		return free
+	}
+
+	victim := state.SpillCandidate(registers)
+
+	if victim == nil {
+		return nil
+	}
+
+	free = victim.Register()
+	state.Spill(victim)
+	return free
+}
+
+// SpillCandidate chooses the variable that is moved to the stack when registers run out.
+// Only variables of the current scope can be spilled so that the variable location stays
+// the same on every code path. Variables that are not used by the current instruction are preferred.
+// If there are none, an operand of the current instruction whose register hasn't been read yet
+// is spilled and loaded from the stack at the point of use.
+// Of the remaining variables, the one with the longest lifetime blocks its register the longest.
+func (state *State) SpillCandidate(registers register.List) *Variable {
+	if state.stackState.locked > 0 {
+		return nil
+	}
+
+	victim := state.spillCandidate(registers, false)
+
+	if victim == nil {
+		victim = state.spillCandidate(registers, true)
+	}
+
+	return victim
+}
+
+// spillCandidate returns the variable with the longest lifetime that can be spilled.
+// Variables used by the current instruction are only included if operands is true.
+func (state *State) spillCandidate(registers register.List, operands bool) *Variable {
+	var victim *Variable
+
+	for _, variable := range state.scopes.Current() {
+		if variable.IsSpilled() || variable.KeepAlive > 0 || !registers.Contains(variable.Register()) {
+			continue
+		}
+
+		if state.InstructionUses(variable.Name) && (!operands || state.IsOperand(variable)) {
+			continue
+		}
+
+		if victim == nil || variable.AliveUntil > victim.AliveUntil || (variable.AliveUntil == victim.AliveUntil && variable.Name < victim.Name) {
+			victim = variable
+		}
+	}
+
+	return victim
+}
+
+// TemporaryRegisterFor returns a register for a temporary value of the given type.
+func (state *State) TemporaryRegisterFor(typ *types.Type) *register.Register {
+	if typ.IsFloat() {
+		return state.TemporaryRegisterIn(state.registers.Float)
+	}
+
+	return state.TemporaryRegisterIn(state.registers.General)
+}
+
+// TemporaryRegisterIn returns a register of the list for a temporary value.
+// If all registers are occupied and no variable can be spilled,
+// the register of a variable that is not used by the current instruction is borrowed.
+// The variable is moved to the stack until ReturnRegisters restores it.
+// Variables of outer scopes can be borrowed because the code between
+// BorrowRegisters and ReturnRegisters is executed on every code path.
+func (state *State) TemporaryRegisterIn(registers register.List) *register.Register {
+	free := state.FindFreeRegisterIn(registers)
+	windows := state.stackState.windows
+
+	if free != nil || len(windows) == 0 || windows[len(windows)-1].locked != state.stackState.locked {
+		return free
+	}
+
+	var victim *Variable
+
+	state.scopes.Each(func(variable *Variable) {
+		if variable.IsSpilled() || variable.KeepAlive > 0 || !registers.Contains(variable.Register()) {
+			return
+		}
+
+		if variable.Register().User() != variable || state.IsOperand(variable) {
+			return
+		}
+
+		if victim == nil || variable.AliveUntil > victim.AliveUntil || (variable.AliveUntil == victim.AliveUntil && variable.Name < victim.Name) {
+			victim = variable
+		}
+	})
+
+	if victim == nil {
+		return nil
+	}
+
+	free = victim.Register()
+	state.Spill(victim)
+	state.stackState.borrowed = append(state.stackState.borrowed, borrowedRegister{variable: victim, register: free})
+	return free
+}
+
+// BorrowRegisters starts a section of code that can borrow registers from variables.
+// Every call needs to be followed by a call to ReturnRegisters.
+func (state *State) BorrowRegisters() {
+	state.stackState.windows = append(state.stackState.windows, borrowWindow{
+		locked:   state.stackState.locked,
+		borrowed: len(state.stackState.borrowed),
+		operands: len(state.stackState.operands),
+	})
+}
+
+// ReturnRegisters loads the variables whose registers have been borrowed in the current section
+// back into their registers. The temporary values must have been freed at this point.
+// The variable registers used as operands in the section are not needed anymore.
+func (state *State) ReturnRegisters() error {
+	window := state.stackState.windows[len(state.stackState.windows)-1]
+	marker := window.borrowed
+	state.stackState.windows = state.stackState.windows[:len(state.stackState.windows)-1]
+
+	if len(state.stackState.operands) > window.operands {
+		state.stackState.operands = state.stackState.operands[:window.operands]
+	}
+
+	for i := len(state.stackState.borrowed) - 1; i >= marker; i-- {
+		borrowed := state.stackState.borrowed[i]
+
+		if !borrowed.register.IsFree() {
+			return errors.New(errors.ExceededMaxVariables)
+		}
+
+		state.LoadSpilled(borrowed.variable, borrowed.register)
+		borrowed.variable.ForceSetRegister(borrowed.register)
+	}
+
+	state.stackState.borrowed = state.stackState.borrowed[:marker]
+	return nil
+}
+
+// Spill stores the variable in a new stack slot and frees its register.
+// Spilled variables stay on the stack until the end of their lifetime.
+func (state *State) Spill(variable *Variable) {
+	state.stackState.size += 8
+	offset := -state.stackState.size
+	state.assembler.StoreRegister(state.registers.Frame, offset, 8, variable.Register())
+	variable.Spill(offset)
+}
+
+// ReserveRegisters spills variables until the given number of general purpose registers is free.
+// Blocks reserve their registers before their scope is created,
+// because the variables of outer scopes can't be spilled inside of the block.
+func (state *State) ReserveRegisters(count int) {
+	for len(state.registers.General)-len(state.registers.General.InUse()) < count {
+		victim := state.SpillCandidate(state.registers.General)
+
+		if victim == nil {
+			return
+		}
+
+		state.Spill(victim)
+	}
+}
+
+// VariableRegister returns the register that holds the value of the variable.
+// Spilled variables are loaded into a temporary register that the caller needs to free.
+func (state *State) VariableRegister(variable *Variable) (*register.Register, error) {
+	if !variable.IsSpilled() {
+		state.AddOperand(variable)
+		return variable.Register(), nil
+	}
+
+	temporary := state.TemporaryRegisterFor(variable.Type)
+
+	if temporary == nil {
+		return nil, errors.New(errors.ExceededMaxVariables)
+	}
+
+	temporary.ForceUse(spilledValue{variable})
+	state.LoadSpilled(variable, temporary)
+	return temporary, nil
+}
+
+// VariableOperand returns the register that holds the value of the variable
+// for a single operation that stores its result in the destination register.
+// If no register is free for a spilled variable, the value of another general purpose register
+// is saved on the stack while the register is borrowed for the operation.
+// The returned function frees or restores the register after the operation.
+func (state *State) VariableOperand(variable *Variable, destination *register.Register) (*register.Register, func(), error) {
+	isOperand := state.IsOperand(variable)
+	operand, err := state.VariableRegister(variable)
+
+	if err == nil {
+		if operand != variable.Register() {
+			return operand, operand.Free, nil
+		}
+
+		// The variable register is not needed after the operation
+		return operand, func() {
+			if !isOperand {
+				state.RemoveOperand(variable)
+			}
+		}, nil
+	}
+
+	if variable.Type.IsFloat() {
+		return nil, nil, err
+	}
+
+	for _, borrowed := range state.registers.General {
+		if borrowed == destination {
+			continue
+		}
+
+		// Spilling a variable would save the borrowed value instead of its own.
+		state.stackState.locked++
+		state.assembler.PushRegister(borrowed)
+		state.LoadSpilled(variable, borrowed)
+
+		return borrowed, func() {
+			state.assembler.PopRegister(borrowed)
+			state.stackState.locked--
+		}, nil
+	}
+
+	return nil, nil, err
+}
+
+// LoadSpilled loads the value of a spilled variable into the register.
+func (state *State) LoadSpilled(variable *Variable, register *register.Register) {
+	state.assembler.LoadRegister(register, state.registers.Frame, variable.StackOffset(), 8)
+}
+
+// StoreSpilled stores the value of the register in the stack slot of a spilled variable.
+func (state *State) StoreSpilled(variable *Variable, register *register.Register) {
+	state.assembler.StoreRegister(state.registers.Frame, variable.StackOffset(), 8, register)
+}
+
+// AddOperand marks the variable as an operand of the current instruction.
+// The register of the variable might be in use until the instruction ends,
+// therefore the variable can't be spilled anymore.
+func (state *State) AddOperand(variable *Variable) {
+	if !state.IsOperand(variable) {
+		state.stackState.operands = append(state.stackState.operands, variable)
+	}
+}
+
+// RemoveOperand allows the variable to be spilled again.
+func (state *State) RemoveOperand(variable *Variable) {
+	for i, operand := range state.stackState.operands {
+		if operand == variable {
+			state.stackState.operands = append(state.stackState.operands[:i], state.stackState.operands[i+1:]...)
+			return
+		}
+	}
+}
+
+// IsOperand returns true if the register of the variable has been used by the current instruction.
+func (state *State) IsOperand(variable *Variable) bool {
+	for _, operand := range state.stackState.operands {
+		if operand == variable {
+			return true
+		}
+	}
+
+	return false
+}
+
+// InstructionUses returns true if the identifier is used in the current instruction.
+func (state *State) InstructionUses(identifier string) bool {
+	return state.InstructionUseCount(identifier) > 0
+}
+
+// InstructionUseCount returns how often the identifier is used in the current instruction.
+func (state *State) InstructionUseCount(identifier string) int {
+	if state.instrCursor >= len(state.instructions) {
+		return 0
+	}
+
+	instr := state.instructions[state.instrCursor]
+	count := 0
+
+	for _, t := range instr.Tokens {
+		count += UseCount(t, identifier)
+	}
+
+	return count
+}
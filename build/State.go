@@ -2,6 +2,7 @@ package build
 
 import (
 	"fmt"
+	"math"
 	"strconv"
 
 	"github.com/akyoto/q/build/assembler"
@@ -29,9 +30,24 @@ type State struct {
 	// Keywords
 	forState    ForState
 	ifState     IfState
+	matchState  MatchState
 	loopState   LoopState
 	expectState ExpectState
 	ensureState EnsureState
+	breakState  BreakState
+
+	// Arrays
+	boundsState BoundsState
+
+	// Printing
+	printState PrintState
+
+	// Spilled variables and structs in the stack frame
+	stackState StackState
+	escaping   map[string]bool
+
+	// Conditions
+	conditionState ConditionState
 
 	// Optimization flags
 	ignoreContracts bool
@@ -59,6 +75,14 @@ func (state *State) CompileInstructions() error {
 func (state *State) Instruction(instr instruction.Instruction, index instruction.Position) error {
 	state.tokenCursor = instr.Position
 	state.instrCursor = index
+	state.stackState.operands = state.stackState.operands[:0]
+	tokens, err := state.ReplaceConstants(instr.Tokens)
+
+	if err != nil {
+		return err
+	}
+
+	instr.Tokens = tokens
 
 	switch instr.Kind {
 	case instruction.Assignment:
@@ -70,9 +94,18 @@ func (state *State) Instruction(instr instruction.Instruction, index instruction
 	case instruction.IfStart:
 		return state.IfStart(instr.Tokens)
 
-	case instruction.IfEnd:
+	case instruction.IfEnd, instruction.ElseIfEnd:
 		return state.IfEnd()
 
+	case instruction.ElseIfStart:
+		return state.ElseIfStart(instr.Tokens)
+
+	case instruction.ElseStart:
+		return state.ElseStart()
+
+	case instruction.ElseEnd:
+		return state.ElseEnd()
+
 	case instruction.ForStart:
 		return state.ForStart(instr.Tokens)
 
@@ -80,11 +113,23 @@ func (state *State) Instruction(instr instruction.Instruction, index instruction
 		return state.ForEnd()
 
 	case instruction.LoopStart:
-		return state.LoopStart()
+		return state.LoopStart(instr.Tokens)
 
 	case instruction.LoopEnd:
 		return state.LoopEnd()
 
+	case instruction.MatchStart:
+		return state.MatchStart(instr.Tokens)
+
+	case instruction.MatchEnd:
+		return state.MatchEnd()
+
+	case instruction.CaseStart:
+		return state.CaseStart(instr.Tokens)
+
+	case instruction.CaseEnd:
+		return state.CaseEnd()
+
 	case instruction.Return:
 		return state.Return(instr.Tokens)
 
@@ -94,6 +139,12 @@ func (state *State) Instruction(instr instruction.Instruction, index instruction
 	case instruction.Ensure:
 		return state.Ensure(instr.Tokens)
 
+	case instruction.Break:
+		return state.Break(instr.Tokens)
+
+	case instruction.Continue:
+		return state.Continue(instr.Tokens)
+
 	case instruction.Invalid:
 		return state.Invalid(instr.Tokens)
 
@@ -102,9 +153,9 @@ func (state *State) Instruction(instr instruction.Instruction, index instruction
 	}
 }
 
-// CompareRegisterExpression compares a register with the result of the expression.
+// CompareRegisterExpression compares a register holding a value of the given type with the result of the expression.
 // If the expression needs to be stored in a temporary register, it will return it.
-func (state *State) CompareRegisterExpression(register *register.Register, expression []token.Token, labelBeforeComparison string) (*register.Register, *types.Type, error) {
+func (state *State) CompareRegisterExpression(register *register.Register, typ *types.Type, expression []token.Token, labelBeforeComparison string) (*register.Register, *types.Type, error) {
 	if len(expression) == 1 {
 		if labelBeforeComparison != "" {
 			state.assembler.AddLabel(labelBeforeComparison)
@@ -120,32 +171,90 @@ func (state *State) CompareRegisterExpression(register *register.Register, expre
 			}
 
 			state.UseVariable(variable)
-			state.assembler.CompareRegisterRegister(register, variable.Register())
+			variableRegister, release, err := state.VariableOperand(variable, register)
+
+			if err != nil {
+				return nil, nil, err
+			}
+
+			state.CompareRegisterRegister(register, variableRegister, typ)
+			release()
 			return nil, variable.Type, nil
 
 		case token.Number:
-			numberString := expression[0].Text()
-			number, err := state.ParseInt(numberString)
+			if typ.IsFloat() {
+				break
+			}
+
+			numberType, err := AdaptNumber(expression[0], types.Int, typ)
+
+			if err != nil {
+				return nil, nil, err
+			}
+
+			number, err := state.ParseInt(expression[0].Text())
 
 			if err != nil {
 				return nil, nil, err
 			}
 
+			// The immediate value is sign-extended from 32 bits.
+			if number < math.MinInt32 || number > math.MaxInt32 {
+				temporary := state.FindFreeRegister()
+
+				if temporary == nil {
+					return nil, nil, errors.New(errors.ExceededMaxVariables)
+				}
+
+				temporary.ForceUse(token.List(expression))
+				state.assembler.MoveRegisterNumber(temporary, uint64(number))
+				state.CompareRegisterRegister(register, temporary, typ)
+				return temporary, numberType, nil
+			}
+
 			state.assembler.CompareRegisterNumber(register, uint64(number))
-			return nil, types.Int, nil
+			return nil, numberType, nil
+
+		case token.Keyword:
+			value, err := BoolValue(expression[0])
+
+			if err != nil {
+				return nil, nil, err
+			}
+
+			state.assembler.CompareRegisterNumber(register, value)
+			return nil, types.Bool, nil
 
 		default:
 			return nil, nil, errors.New(errors.InvalidExpression)
 		}
+
+		// Floating-point numbers are loaded into a temporary register
+		temporary := state.FindFreeRegisterFor(typ)
+
+		if temporary == nil {
+			return nil, nil, errors.New(errors.ExceededMaxVariables)
+		}
+
+		temporary.ForceUse(token.List(expression))
+		err := state.FloatNumberToRegister(expression[0], typ, temporary)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		state.CompareRegisterRegister(register, temporary, typ)
+		return temporary, typ, nil
 	}
 
-	temporary := state.registers.General.FindFree()
+	temporary := state.TemporaryRegisterIn(state.registers.ListFor(register))
 
 	if temporary == nil {
 		return nil, nil, errors.New(errors.ExceededMaxVariables)
 	}
 
-	typ, err := state.TokensToRegister(expression, temporary)
+	temporary.ForceUse(token.List(expression))
+	expressionType, err := state.TokensToRegister(expression, temporary)
 
 	if err != nil {
 		return nil, nil, err
@@ -155,8 +264,18 @@ func (state *State) CompareRegisterExpression(register *register.Register, expre
 		state.assembler.AddLabel(labelBeforeComparison)
 	}
 
-	state.assembler.CompareRegisterRegister(register, temporary)
-	return temporary, typ, nil
+	state.CompareRegisterRegister(register, temporary, typ)
+	return temporary, expressionType, nil
+}
+
+// CompareRegisterRegister compares two registers holding values of the given type.
+func (state *State) CompareRegisterRegister(registerA *register.Register, registerB *register.Register, typ *types.Type) {
+	if typ.IsFloat() {
+		state.assembler.FloatCompareRegisterRegister(registerA, registerB, byte(typ.Size))
+		return
+	}
+
+	state.assembler.CompareRegisterRegister(registerA, registerB)
 }
 
 // PopScope pops the last scope on the stack and returns
@@ -179,7 +298,7 @@ func (state *State) UseVariable(variable *Variable) {
 
 // InLoop returns true if we're currently in a loop body.
 func (state *State) InLoop() bool {
-	return len(state.forState.stack) > 0 || len(state.loopState.labels) > 0
+	return len(state.forState.stack) > 0 || len(state.loopState.stack) > 0
 }
 
 // Invalid handles invalid instructions.
@@ -200,7 +319,20 @@ func (state *State) Invalid(tokens []token.Token) error {
 
 // ParseInt parses an integer number.
 func (state *State) ParseInt(numberString string) (int64, error) {
-	number, err := strconv.ParseInt(numberString, 10, 64)
+	return ParseInt(numberString)
+}
+
+// ParseInt parses an integer number.
+// Numbers that only fit into an unsigned 64-bit integer keep their bits.
+func ParseInt(numberString string) (int64, error) {
+	digits, base := IntegerDigits(numberString)
+	number, err := strconv.ParseInt(digits, base, 64)
+
+	if err != nil {
+		var unsigned uint64
+		unsigned, err = strconv.ParseUint(digits, base, 64)
+		number = int64(unsigned)
+	}
 
 	if err != nil {
 		return 0, errors.New(&errors.NotANumber{
@@ -0,0 +1,279 @@
+package build
+
+import (
+	"strings"
+	"sync/atomic"
+
+	"github.com/akyoto/q/build/errors"
+	"github.com/akyoto/q/build/expression"
+	"github.com/akyoto/q/build/register"
+	"github.com/akyoto/q/build/token"
+	"github.com/akyoto/q/build/types"
+)
+
+// Struct represents a data structure or an enumeration declared in a file.
+// The field types are resolved after all files have been scanned
+// so that a struct can refer to structs that are declared later or in other files.
+type Struct struct {
+	Type      *types.Type
+	File      *File
+	fields    []*structField
+	resolved  bool
+	resolving bool
+}
+
+// structField is a field whose type has not been resolved yet.
+// The tokens up to the type name are kept for error messages.
+type structField struct {
+	*types.Field
+	typeName    string
+	declaration []token.Token
+}
+
+// ResolveTypes calculates the field offsets and the sizes of all structs.
+func (env *Environment) ResolveTypes() error {
+	for _, structure := range env.Structs {
+		err := env.ResolveStruct(structure)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ResolveStruct resolves the field types of a single struct.
+// Structs that are stored inside of the struct are resolved first
+// because their size is needed for the offsets of the following fields.
+// Every field is aligned to a multiple of its alignment by inserting padding before it
+// and the size is padded to a multiple of the struct alignment so that the fields stay aligned in arrays.
+// Packed structs don't have any padding.
+func (env *Environment) ResolveStruct(structure *Struct) error {
+	// Enums don't have fields, their size is always known.
+	if structure.resolved || structure.Type.IsEnum() {
+		return nil
+	}
+
+	structure.resolving = true
+	defer func() { structure.resolving = false }()
+	typ := structure.Type
+
+	for _, field := range structure.fields {
+		// Pointers don't need to know the size of the struct they point to.
+		if !strings.HasPrefix(field.typeName, "*") {
+			elementName := strings.TrimLeft(field.typeName, "[]0123456789")
+			dependency := env.Structs[elementName]
+
+			if dependency != nil && dependency.resolving {
+				return NewError(errors.New(errors.StructCycle), structure.File.path, field.declaration, nil)
+			}
+
+			if dependency != nil {
+				err := env.ResolveStruct(dependency)
+
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		field.Type = env.Type(field.typeName)
+
+		if field.Type == nil {
+			return NewError(errors.New(env.UnknownTypeError(field.typeName)), structure.File.path, field.declaration, nil)
+		}
+
+		if typ.Packed {
+			field.Offset = typ.Size
+		} else {
+			field.Offset = alignTo(typ.Size, field.Type.Alignment())
+
+			if field.Type.Alignment() > typ.Align {
+				typ.Align = field.Type.Alignment()
+			}
+		}
+
+		typ.Fields = append(typ.Fields, field.Field)
+		typ.Size = field.Offset + field.Type.Size
+	}
+
+	if typ.Align == 0 {
+		typ.Align = 1
+	}
+
+	typ.Size = alignTo(typ.Size, typ.Align)
+	structure.resolved = true
+	return nil
+}
+
+// alignTo rounds the offset up to the next multiple of the alignment.
+func alignTo(offset uint, alignment uint) uint {
+	return (offset + alignment - 1) / alignment * alignment
+}
+
+// StructLiteral allocates a struct and initializes the fields listed in a literal like `Point{x: 1, y: 2}`.
+// Fields that are not listed are zero because the allocated memory is zero-initialized.
+// Arrays created with `[4]Int()` are allocated the same way.
+func (state *State) StructLiteral(literal *expression.Expression, typ *types.Type) error {
+	if len(typ.Fields) == 0 && !typ.IsArray() {
+		return errors.New(&errors.InvalidType{Name: typ.Name, Expected: "struct"})
+	}
+
+	structRegister := literal.Register
+
+	if structRegister == nil {
+		structRegister = state.FindFreeRegister()
+
+		if structRegister == nil {
+			return errors.New(errors.ExceededMaxVariables)
+		}
+	}
+
+	// The address needs to survive the calculation of the field values.
+	if structRegister.IsFree() {
+		structRegister.ForceUse(literal)
+		defer structRegister.Free()
+	}
+
+	if state.IsStackAllocation(literal, typ) {
+		state.AllocateStack(structRegister, typ.Size)
+	} else {
+		state.Allocate(structRegister, typ.Size)
+	}
+
+	literal.Type = typ
+	initialized := map[string]bool{}
+
+	for _, initializer := range literal.Children {
+		if initializer.Token.Text() != ":" || len(initializer.Children) != 2 || !initializer.Children[0].IsLeaf() {
+			return errors.New(errors.MissingFieldName)
+		}
+
+		fieldName := initializer.Children[0].Token.Text()
+		field := typ.FieldByName(fieldName)
+
+		if field == nil {
+			return errors.New(UnknownFieldError(fieldName, typ))
+		}
+
+		if initialized[fieldName] {
+			return errors.New(&errors.DuplicateField{Name: fieldName})
+		}
+
+		initialized[fieldName] = true
+		err := state.StoreExpression(structRegister, int32(field.Offset), field.Type, initializer.Children[1])
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Allocate reserves zero-initialized memory of the given size on the heap
+// and moves its address into the register.
+// The registers modified by the runtime allocator are saved unless they receive the address.
+func (state *State) Allocate(address *register.Register, size uint) {
+	allocator := state.environment.Allocator
+	var modified register.List
+
+	for _, id := range allocator.UsedRegisterIDs() {
+		reg := state.registers.ByID(id)
+
+		if reg != address {
+			modified = append(modified, reg)
+		}
+	}
+
+	saved := state.SaveRegisters(modified)
+	state.assembler.MoveRegisterNumber(state.registers.Call[0], uint64(size))
+	state.assembler.Call(allocator.Name)
+
+	if address != state.registers.ReturnValue[0] {
+		state.assembler.MoveRegisterRegister(address, state.registers.ReturnValue[0])
+	}
+
+	state.RestoreRegisters(saved)
+	atomic.AddInt32(&allocator.CallCount, 1)
+}
+
+// AllocateStack reserves memory of the given size in the stack frame,
+// fills it with zeros and moves its address into the register.
+// Every struct literal has its own memory that is reused when the literal is executed again.
+func (state *State) AllocateStack(address *register.Register, size uint) {
+	size = alignTo(size, 8)
+	state.stackState.size += int32(size)
+	offset := -state.stackState.size
+
+	for cleared := int32(0); cleared < int32(size); cleared += 8 {
+		state.assembler.StoreNumber(state.registers.Frame, offset+cleared, 8, 0)
+	}
+
+	state.assembler.MoveRegisterRegister(address, state.registers.Frame)
+	state.assembler.SubRegisterNumber(address, uint64(-offset))
+}
+
+// StoreExpression stores the result of the expression
+// at the memory address in the base register plus the offset.
+func (state *State) StoreExpression(base *register.Register, offset int32, typ *types.Type, value *expression.Expression) error {
+	if value.IsLeaf() {
+		return state.StoreTokens(base, offset, typ, []token.Token{value.Token})
+	}
+
+	valueRegister := state.FindFreeRegisterFor(typ)
+
+	if valueRegister == nil {
+		return errors.New(errors.ExceededMaxVariables)
+	}
+
+	valueRegister.ForceUse(value)
+	defer valueRegister.Free()
+	valueType, err := state.ExpressionToRegister(value, valueRegister)
+
+	if err != nil {
+		return err
+	}
+
+	if valueType != typ {
+		return errors.New(&errors.InvalidType{Name: valueType.String(), Expected: typ.String()})
+	}
+
+	return state.StoreValue(base, offset, typ, valueRegister)
+}
+
+// StoreValue stores the value in the register at the memory address in the base register plus the offset.
+// Structs are copied because the register only holds their address.
+func (state *State) StoreValue(base *register.Register, offset int32, typ *types.Type, value *register.Register) error {
+	if len(typ.Fields) == 0 {
+		state.assembler.StoreRegister(base, offset, byte(typ.Size), value)
+		return nil
+	}
+
+	return state.CopyMemory(base, offset, value, typ.Size)
+}
+
+// CopyMemory copies the given number of bytes from the address in the source register
+// to the address in the base register plus the offset.
+func (state *State) CopyMemory(base *register.Register, offset int32, source *register.Register, size uint) error {
+	temporary := state.FindFreeRegister()
+
+	if temporary == nil {
+		return errors.New(errors.ExceededMaxVariables)
+	}
+
+	temporary.ForceUse(source)
+	defer temporary.Free()
+	copied := int32(0)
+
+	for _, byteCount := range []int32{8, 4, 2, 1} {
+		for int32(size)-copied >= byteCount {
+			state.assembler.LoadRegister(temporary, source, copied, byte(byteCount))
+			state.assembler.StoreRegister(base, offset+copied, byte(byteCount), temporary)
+			copied += byteCount
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,175 @@
+package build
+
+import (
+	"strings"
+
+	"github.com/akyoto/q/build/errors"
+	"github.com/akyoto/q/build/expression"
+	"github.com/akyoto/q/build/register"
+	"github.com/akyoto/q/build/token"
+	"github.com/akyoto/q/build/types"
+)
+
+// TextFieldToRegister loads a field of a text into the register of the dot operation.
+// The only field of a text is its length which is stored in front of the first byte.
+func (state *State) TextFieldToRegister(access *expression.Expression, textRegister *register.Register) error {
+	fieldName := access.Children[1].Token.Text()
+	access.Type = TextFieldType(fieldName)
+
+	if access.Type == nil {
+		return errors.New(&errors.UnknownField{Name: fieldName, TypeName: types.Text.Name})
+	}
+
+	state.assembler.LoadRegister(access.Register, textRegister, -types.TextLengthSize, types.TextLengthSize)
+	return nil
+}
+
+// TextFieldType returns the type of a text field or nil if the field doesn't exist.
+func TextFieldType(fieldName string) *types.Type {
+	if fieldName != "length" {
+		return nil
+	}
+
+	return types.Int
+}
+
+// textPart is either a literal part of a text or a value in braces.
+type textPart struct {
+	literal string
+	value   *expression.Expression
+}
+
+// SplitInterpolation splits a text into literal parts and the values in braces like `{x}`.
+// Literal braces are written as `{{` and `}}`.
+// The caller needs to close the expressions of the values.
+func (state *State) SplitInterpolation(text string) ([]textPart, error) {
+	var (
+		parts   []textPart
+		literal []byte
+	)
+
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+
+		if c == '}' && i+1 < len(text) && text[i+1] == '}' {
+			literal = append(literal, '}')
+			i++
+			continue
+		}
+
+		if c != '{' {
+			literal = append(literal, c)
+			continue
+		}
+
+		if i+1 < len(text) && text[i+1] == '{' {
+			literal = append(literal, '{')
+			i++
+			continue
+		}
+
+		end := strings.IndexByte(text[i+1:], '}')
+
+		if end == -1 {
+			closeTextParts(parts)
+			return nil, errors.New(&errors.MissingCharacter{Character: "}"})
+		}
+
+		value, err := state.interpolatedValue(text[i+1 : i+1+end])
+
+		if err != nil {
+			closeTextParts(parts)
+			return nil, err
+		}
+
+		if len(literal) > 0 {
+			parts = append(parts, textPart{literal: string(literal)})
+			literal = literal[:0]
+		}
+
+		parts = append(parts, textPart{value: value})
+		i += end + 1
+	}
+
+	if len(literal) > 0 || len(parts) == 0 {
+		parts = append(parts, textPart{literal: string(literal)})
+	}
+
+	return parts, nil
+}
+
+// interpolatedValue parses the expression inside of the braces of an interpolated text.
+func (state *State) interpolatedValue(source string) (*expression.Expression, error) {
+	tokens, processed := token.Tokenize([]byte(source+"\n"), nil)
+
+	if int(processed) != len(source)+1 {
+		return nil, errors.New(&errors.UnknownExpression{Expression: source[processed:]})
+	}
+
+	tokens = tokens[:len(tokens)-1]
+
+	if len(tokens) == 0 {
+		return nil, errors.New(errors.MissingInterpolationValue)
+	}
+
+	tokens, err := state.ReplaceConstants(tokens)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return expression.FromTokens(tokens)
+}
+
+// InterpolatedIdentifiers returns the identifiers inside of the braces of a text literal.
+// Literal braces and values that can't be tokenized are skipped.
+func InterpolatedIdentifiers(text string) []string {
+	var identifiers []string
+
+	for i := 0; i < len(text); i++ {
+		if text[i] != '{' {
+			continue
+		}
+
+		if i+1 < len(text) && text[i+1] == '{' {
+			i++
+			continue
+		}
+
+		end := strings.IndexByte(text[i+1:], '}')
+
+		if end == -1 {
+			break
+		}
+
+		tokens, _ := token.Tokenize([]byte(text[i+1:i+1+end]+"\n"), nil)
+
+		for _, t := range tokens {
+			if t.Kind == token.Identifier {
+				identifiers = append(identifiers, t.Text())
+			}
+		}
+
+		i += end + 1
+	}
+
+	return identifiers
+}
+
+// literalText returns the text of the parts and true if none of the parts is a value.
+func literalText(parts []textPart) (string, bool) {
+	if len(parts) == 1 && parts[0].value == nil {
+		return parts[0].literal, true
+	}
+
+	return "", false
+}
+
+// closeTextParts frees the expressions of the values.
+func closeTextParts(parts []textPart) {
+	for _, part := range parts {
+		if part.value != nil {
+			part.value.Close()
+		}
+	}
+}
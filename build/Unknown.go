@@ -71,13 +71,13 @@ func (state *State) UnknownVariableError(variableName string) error {
 // UnknownFunctionError produces an unknown function error
 // and tries to guess which function the user was trying to type.
 func (env *Environment) UnknownFunctionError(functionName string) error {
-	knownFunctions := make([]string, 0, len(env.Functions)+len(BuiltinFunctions))
+	knownFunctions := make([]string, 0, len(env.Overloads)+len(BuiltinFunctions))
 
 	for builtin := range BuiltinFunctions {
 		knownFunctions = append(knownFunctions, builtin)
 	}
 
-	for function := range env.Functions {
+	for function := range env.Overloads {
 		knownFunctions = append(knownFunctions, function)
 	}
 
@@ -133,6 +133,10 @@ func UnknownFieldError(field string, typ *types.Type) error {
 		knownFields = append(knownFields, field.Name)
 	}
 
+	if len(knownFields) == 0 {
+		return &errors.UnknownField{TypeName: typ.Name, Name: field}
+	}
+
 	// Suggest a type name based on the similarity to known functions
 	sort.Slice(knownFields, func(a, b int) bool {
 		aSimilarity := similarity.JaroWinkler(field, knownFields[a])
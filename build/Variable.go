@@ -17,7 +17,10 @@ type Variable struct {
 	LastAssignUsed bool
 	Used           bool
 	Mutable        bool
+	Modified       bool
+	IsParameter    bool
 	register       *register.Register
+	stackOffset    int32
 }
 
 // Register returns the register the variable refers to.
@@ -41,6 +44,24 @@ func (variable *Variable) ForceSetRegister(register *register.Register) {
 	register.ForceUse(variable)
 }
 
+// Spill frees the register of the variable which now lives in
+// the stack slot at the given offset relative to the frame pointer.
+func (variable *Variable) Spill(stackOffset int32) {
+	variable.register.Free()
+	variable.register = nil
+	variable.stackOffset = stackOffset
+}
+
+// IsSpilled returns true if the variable lives on the stack instead of a register.
+func (variable *Variable) IsSpilled() bool {
+	return variable.register == nil && variable.stackOffset != 0
+}
+
+// StackOffset returns the position of a spilled variable relative to the frame pointer.
+func (variable *Variable) StackOffset() int32 {
+	return variable.stackOffset
+}
+
 // String returns the name of the variable.
 func (variable *Variable) String() string {
 	return variable.Name
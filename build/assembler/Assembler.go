@@ -1,11 +1,14 @@
 package assembler
 
 import (
+	"encoding/binary"
 	"log"
 
 	"github.com/akyoto/asm"
 	"github.com/akyoto/q/build/assembler/instructions"
+	"github.com/akyoto/q/build/assembler/mnemonics"
 	"github.com/akyoto/q/build/register"
+	"github.com/akyoto/q/build/types"
 )
 
 // Assembler produces machine code.
@@ -41,15 +44,56 @@ func (a *Assembler) AddString(text string) uint32 {
 	return a.final.Strings.Add(text)
 }
 
+// AddText adds a text with its length stored in front of it
+// and returns the address of the first byte.
+func (a *Assembler) AddText(text string) uint32 {
+	data := make([]byte, types.TextLengthSize, types.TextLengthSize+len(text))
+	binary.LittleEndian.PutUint64(data, uint64(len(text)))
+	data = append(data, text...)
+	return a.final.Strings.Add(string(data)) + types.TextLengthSize
+}
+
 // Finalize generates the final assembly code.
 func (a *Assembler) Finalize() *asm.Assembler {
 	for _, instr := range a.Instructions {
 		instr.Exec(a.final)
 	}
 
+	for _, instr := range a.Instructions {
+		jump, isJump := instr.(*instructions.Jump)
+
+		if isJump {
+			jump.Resolve(a.final)
+		}
+	}
+
 	return a.final
 }
 
+// AddStackFrame reserves stack memory below the frame pointer.
+// The frame pointer of the caller is saved after the function label
+// and restored before every return.
+func (a *Assembler) AddStackFrame(frame *register.Register, stack *register.Register, size uint64) {
+	body := a.Instructions
+	a.Instructions = make([]instruction, 0, len(body)+8)
+	a.Instructions = append(a.Instructions, body[0])
+	a.PushRegister(frame)
+	a.MoveRegisterRegister(frame, stack)
+
+	if size > 0 {
+		a.SubRegisterNumber(stack, size)
+	}
+
+	for _, instr := range body[1:] {
+		if instr.Name() == mnemonics.RET {
+			a.MoveRegisterRegister(stack, frame)
+			a.PopRegister(frame)
+		}
+
+		a.Instructions = append(a.Instructions, instr)
+	}
+}
+
 // UseRegisterID marks the given register ID as used.
 func (a *Assembler) UseRegisterID(newID register.ID) {
 	for _, id := range a.usedRegisterIDs {
@@ -166,7 +210,7 @@ func (a *Assembler) doRegisterAddress(mnemonic string, destination *register.Reg
 }
 
 // doMemoryNumber adds an instruction using a memory address and a number.
-func (a *Assembler) doMemoryNumber(mnemonic string, destination *register.Register, offset byte, byteCount byte, number uint64) {
+func (a *Assembler) doMemoryNumber(mnemonic string, destination *register.Register, offset int32, byteCount byte, number uint64) {
 	instr := &instructions.MemoryNumber{
 		Destination: destination,
 		Offset:      offset,
@@ -185,7 +229,7 @@ func (a *Assembler) doMemoryNumber(mnemonic string, destination *register.Regist
 }
 
 // doMemoryRegister adds an instruction using a memory address and a register.
-func (a *Assembler) doMemoryRegister(mnemonic string, destination *register.Register, offset byte, byteCount byte, source *register.Register) {
+func (a *Assembler) doMemoryRegister(mnemonic string, destination *register.Register, offset int32, byteCount byte, source *register.Register) {
 	instr := &instructions.MemoryRegister{
 		Destination: destination,
 		Offset:      offset,
@@ -206,7 +250,7 @@ func (a *Assembler) doMemoryRegister(mnemonic string, destination *register.Regi
 }
 
 // doRegisterMemory adds an instruction using a register target and a source memory address.
-func (a *Assembler) doRegisterMemory(mnemonic string, destination *register.Register, source *register.Register, offset byte, byteCount byte) {
+func (a *Assembler) doRegisterMemory(mnemonic string, destination *register.Register, source *register.Register, offset int32, byteCount byte) {
 	instr := &instructions.RegisterMemory{
 		Destination: destination,
 		Source:      source,
@@ -61,6 +61,62 @@ func (a *Assembler) JumpIfGreaterOrEqual(label string) {
 	a.doJump(mnemonics.JGE, label)
 }
 
+func (a *Assembler) JumpIfAboveOrEqual(label string) {
+	a.doJump(mnemonics.JAE, label)
+}
+
+func (a *Assembler) JumpIfAbove(label string) {
+	a.doJump(mnemonics.JA, label)
+}
+
+func (a *Assembler) JumpIfBelow(label string) {
+	a.doJump(mnemonics.JB, label)
+}
+
+func (a *Assembler) JumpIfBelowOrEqual(label string) {
+	a.doJump(mnemonics.JBE, label)
+}
+
+func (a *Assembler) SetIfEqual(destination *register.Register) {
+	a.doRegister(mnemonics.SETE, destination)
+}
+
+func (a *Assembler) SetIfNotEqual(destination *register.Register) {
+	a.doRegister(mnemonics.SETNE, destination)
+}
+
+func (a *Assembler) SetIfLess(destination *register.Register) {
+	a.doRegister(mnemonics.SETL, destination)
+}
+
+func (a *Assembler) SetIfLessOrEqual(destination *register.Register) {
+	a.doRegister(mnemonics.SETLE, destination)
+}
+
+func (a *Assembler) SetIfGreater(destination *register.Register) {
+	a.doRegister(mnemonics.SETG, destination)
+}
+
+func (a *Assembler) SetIfGreaterOrEqual(destination *register.Register) {
+	a.doRegister(mnemonics.SETGE, destination)
+}
+
+func (a *Assembler) SetIfAboveOrEqual(destination *register.Register) {
+	a.doRegister(mnemonics.SETAE, destination)
+}
+
+func (a *Assembler) SetIfAbove(destination *register.Register) {
+	a.doRegister(mnemonics.SETA, destination)
+}
+
+func (a *Assembler) SetIfBelow(destination *register.Register) {
+	a.doRegister(mnemonics.SETB, destination)
+}
+
+func (a *Assembler) SetIfBelowOrEqual(destination *register.Register) {
+	a.doRegister(mnemonics.SETBE, destination)
+}
+
 func (a *Assembler) IncreaseRegister(destination *register.Register) {
 	a.doRegister(mnemonics.INC, destination)
 }
@@ -81,30 +137,59 @@ func (a *Assembler) DivRegister(destination *register.Register) {
 	a.doRegister(mnemonics.DIV, destination)
 }
 
+func (a *Assembler) UnsignedDivRegister(destination *register.Register) {
+	a.doRegister(mnemonics.UDIV, destination)
+}
+
 func (a *Assembler) SignExtendToDX(destination *register.Register) {
 	a.doRegister(mnemonics.CDQ, destination)
 }
 
+// MoveRegisterRegister also moves values between general purpose and floating-point registers.
 func (a *Assembler) MoveRegisterRegister(destination *register.Register, source *register.Register) {
-	a.doRegisterRegister(mnemonics.MOV, destination, source)
+	switch {
+	case destination.IsFloat() && source.IsFloat():
+		a.doRegisterRegister(mnemonics.MOVSD, destination, source)
+
+	case destination.IsFloat() || source.IsFloat():
+		a.doRegisterRegister(mnemonics.MOVQ, destination, source)
+
+	default:
+		a.doRegisterRegister(mnemonics.MOV, destination, source)
+	}
+}
+
+// SignExtend fills the upper bits of the register with the sign bit of its lowest bytes.
+func (a *Assembler) SignExtend(destination *register.Register, source *register.Register, byteCount byte) {
+	a.doRegisterRegister(extendMnemonic(byteCount, mnemonics.MOVSXB, mnemonics.MOVSXW, mnemonics.MOVSXD), destination, source)
+}
+
+// ZeroExtend clears the upper bits of the register above its lowest bytes.
+func (a *Assembler) ZeroExtend(destination *register.Register, source *register.Register, byteCount byte) {
+	a.doRegisterRegister(extendMnemonic(byteCount, mnemonics.MOVZXB, mnemonics.MOVZXW, mnemonics.MOVZXD), destination, source)
 }
 
 func (a *Assembler) MoveRegisterNumber(destination *register.Register, number uint64) {
 	a.doRegisterNumber(mnemonics.MOV, destination, number)
 }
 
-func (a *Assembler) StoreNumber(destination *register.Register, offset byte, byteCount byte, number uint64) {
+func (a *Assembler) StoreNumber(destination *register.Register, offset int32, byteCount byte, number uint64) {
 	a.doMemoryNumber(mnemonics.STORE, destination, offset, byteCount, number)
 }
 
-func (a *Assembler) StoreRegister(destination *register.Register, offset byte, byteCount byte, source *register.Register) {
+func (a *Assembler) StoreRegister(destination *register.Register, offset int32, byteCount byte, source *register.Register) {
 	a.doMemoryRegister(mnemonics.STORE, destination, offset, byteCount, source)
 }
 
-func (a *Assembler) LoadRegister(destination *register.Register, source *register.Register, offset byte, byteCount byte) {
+func (a *Assembler) LoadRegister(destination *register.Register, source *register.Register, offset int32, byteCount byte) {
 	a.doRegisterMemory(mnemonics.LOAD, destination, source, offset, byteCount)
 }
 
+// LoadRegisterUnsigned zero-extends the loaded value instead of sign-extending it.
+func (a *Assembler) LoadRegisterUnsigned(destination *register.Register, source *register.Register, offset int32, byteCount byte) {
+	a.doRegisterMemory(mnemonics.LOADU, destination, source, offset, byteCount)
+}
+
 func (a *Assembler) MoveRegisterAddress(destination *register.Register, address uint32) {
 	a.doRegisterAddress(mnemonics.MOV, destination, address)
 }
@@ -137,6 +222,123 @@ func (a *Assembler) MulRegisterRegister(destination *register.Register, source *
 	a.doRegisterRegister(mnemonics.MUL, destination, source)
 }
 
+func (a *Assembler) AndRegisterRegister(destination *register.Register, source *register.Register) {
+	a.doRegisterRegister(mnemonics.AND, destination, source)
+}
+
+func (a *Assembler) AndRegisterNumber(destination *register.Register, number uint64) {
+	a.doRegisterNumber(mnemonics.AND, destination, number)
+}
+
+func (a *Assembler) OrRegisterRegister(destination *register.Register, source *register.Register) {
+	a.doRegisterRegister(mnemonics.OR, destination, source)
+}
+
+func (a *Assembler) OrRegisterNumber(destination *register.Register, number uint64) {
+	a.doRegisterNumber(mnemonics.OR, destination, number)
+}
+
+func (a *Assembler) XorRegisterRegister(destination *register.Register, source *register.Register) {
+	a.doRegisterRegister(mnemonics.XOR, destination, source)
+}
+
+func (a *Assembler) XorRegisterNumber(destination *register.Register, number uint64) {
+	a.doRegisterNumber(mnemonics.XOR, destination, number)
+}
+
+// ShiftLeftRegisterRegister expects the bit count to be in rcx.
+func (a *Assembler) ShiftLeftRegisterRegister(destination *register.Register, rcx *register.Register) {
+	a.doRegisterRegister(mnemonics.SHL, destination, rcx)
+}
+
+func (a *Assembler) ShiftLeftRegisterNumber(destination *register.Register, number uint64) {
+	a.doRegisterNumber(mnemonics.SHL, destination, number)
+}
+
+// ShiftRightRegisterRegister expects the bit count to be in rcx.
+func (a *Assembler) ShiftRightRegisterRegister(destination *register.Register, rcx *register.Register) {
+	a.doRegisterRegister(mnemonics.SAR, destination, rcx)
+}
+
+func (a *Assembler) ShiftRightRegisterNumber(destination *register.Register, number uint64) {
+	a.doRegisterNumber(mnemonics.SAR, destination, number)
+}
+
+// ShiftRightLogicalRegisterRegister expects the bit count to be in rcx.
+func (a *Assembler) ShiftRightLogicalRegisterRegister(destination *register.Register, rcx *register.Register) {
+	a.doRegisterRegister(mnemonics.SHR, destination, rcx)
+}
+
+func (a *Assembler) ShiftRightLogicalRegisterNumber(destination *register.Register, number uint64) {
+	a.doRegisterNumber(mnemonics.SHR, destination, number)
+}
+
 func (a *Assembler) MulRegisterNumber(destination *register.Register, number uint64) {
 	a.doRegisterNumber(mnemonics.MUL, destination, number)
 }
+
+// LoadFloatAddress loads a floating-point number with the given size from the data section.
+func (a *Assembler) LoadFloatAddress(destination *register.Register, address uint32, byteCount byte) {
+	a.doRegisterAddress(floatMnemonic(byteCount, mnemonics.MOVSD, mnemonics.MOVSS), destination, address)
+}
+
+func (a *Assembler) FloatAddRegisterRegister(destination *register.Register, source *register.Register, byteCount byte) {
+	a.doRegisterRegister(floatMnemonic(byteCount, mnemonics.ADDSD, mnemonics.ADDSS), destination, source)
+}
+
+func (a *Assembler) FloatSubRegisterRegister(destination *register.Register, source *register.Register, byteCount byte) {
+	a.doRegisterRegister(floatMnemonic(byteCount, mnemonics.SUBSD, mnemonics.SUBSS), destination, source)
+}
+
+func (a *Assembler) FloatMulRegisterRegister(destination *register.Register, source *register.Register, byteCount byte) {
+	a.doRegisterRegister(floatMnemonic(byteCount, mnemonics.MULSD, mnemonics.MULSS), destination, source)
+}
+
+func (a *Assembler) FloatDivRegisterRegister(destination *register.Register, source *register.Register, byteCount byte) {
+	a.doRegisterRegister(floatMnemonic(byteCount, mnemonics.DIVSD, mnemonics.DIVSS), destination, source)
+}
+
+// FloatCompareRegisterRegister sets the flags like an unsigned integer comparison.
+func (a *Assembler) FloatCompareRegisterRegister(destination *register.Register, source *register.Register, byteCount byte) {
+	a.doRegisterRegister(floatMnemonic(byteCount, mnemonics.UCOMISD, mnemonics.UCOMISS), destination, source)
+}
+
+// IntToFloat converts the integer to a floating-point number with the given size.
+func (a *Assembler) IntToFloat(destination *register.Register, source *register.Register, byteCount byte) {
+	a.doRegisterRegister(floatMnemonic(byteCount, mnemonics.CVTSI2SD, mnemonics.CVTSI2SS), destination, source)
+}
+
+// FloatToInt converts the floating-point number with the given size to an integer.
+// The fractional part is truncated.
+func (a *Assembler) FloatToInt(destination *register.Register, source *register.Register, byteCount byte) {
+	a.doRegisterRegister(floatMnemonic(byteCount, mnemonics.CVTTSD2SI, mnemonics.CVTTSS2SI), destination, source)
+}
+
+// FloatToFloat converts the floating-point number to the precision of the given size.
+func (a *Assembler) FloatToFloat(destination *register.Register, source *register.Register, byteCount byte) {
+	a.doRegisterRegister(floatMnemonic(byteCount, mnemonics.CVTSS2SD, mnemonics.CVTSD2SS), destination, source)
+}
+
+// floatMnemonic chooses the double precision mnemonic for 8 bytes
+// and the single precision mnemonic for 4 bytes.
+func floatMnemonic(byteCount byte, double string, single string) string {
+	if byteCount == 4 {
+		return single
+	}
+
+	return double
+}
+
+// extendMnemonic chooses the extension mnemonic for the number of bytes to extend.
+func extendMnemonic(byteCount byte, byteMnemonic string, wordMnemonic string, doubleWordMnemonic string) string {
+	switch byteCount {
+	case 1:
+		return byteMnemonic
+
+	case 2:
+		return wordMnemonic
+
+	default:
+		return doubleWordMnemonic
+	}
+}
@@ -3,6 +3,7 @@ package assembler
 import (
 	"github.com/akyoto/q/build/assembler/instructions"
 	"github.com/akyoto/q/build/assembler/mnemonics"
+	"github.com/akyoto/q/build/register"
 )
 
 // Optimize optimizes the assembler instructions for improved performance.
@@ -84,6 +85,12 @@ func (a *Assembler) Optimize() {
 
 					continue
 				}
+
+				// Memory accesses read the register if it holds the address or the stored value
+				if usesRegisterInMemoryAccess(checkInstr, source) {
+					canOptimize = false
+					break
+				}
 			}
 
 			if !canOptimize {
@@ -95,3 +102,21 @@ func (a *Assembler) Optimize() {
 		}
 	}
 }
+
+// usesRegisterInMemoryAccess returns true if the instruction is a memory access
+// that involves the register as an address or as a value.
+func usesRegisterInMemoryAccess(instr instruction, reg *register.Register) bool {
+	switch instr := instr.(type) {
+	case *instructions.MemoryNumber:
+		return instr.Destination == reg
+
+	case *instructions.MemoryRegister:
+		return instr.Destination == reg || instr.Source == reg
+
+	case *instructions.RegisterMemory:
+		return instr.Destination == reg || instr.Source == reg
+
+	default:
+		return false
+	}
+}
@@ -1,6 +1,7 @@
 package instructions
 
 import (
+	"encoding/binary"
 	"fmt"
 
 	"github.com/akyoto/asm"
@@ -10,7 +11,9 @@ import (
 // Jump is used for instructions requiring a label.
 type Jump struct {
 	Base
-	Label string
+	Label   string
+	pointer uint32
+	forward bool
 }
 
 // Exec writes the instruction to the final assembler.
@@ -21,29 +24,27 @@ func (instr *Jump) Exec(a *asm.Assembler) {
 	case mnemonics.CALL:
 		a.Call(instr.Label)
 
-	case mnemonics.JE:
-		a.JumpIfEqual(instr.Label)
-
-	case mnemonics.JNE:
-		a.JumpIfNotEqual(instr.Label)
-
-	case mnemonics.JG:
-		a.JumpIfGreater(instr.Label)
+	default:
+		instr.pointer, instr.forward = encodeJump(a, instr.Mnemonic, instr.Label)
+	}
 
-	case mnemonics.JGE:
-		a.JumpIfGreaterOrEqual(instr.Label)
+	instr.size = byte(a.Len() - start)
+}
 
-	case mnemonics.JL:
-		a.JumpIfLess(instr.Label)
+// Resolve writes the offset of forward jumps after all labels have been added.
+func (instr *Jump) Resolve(a *asm.Assembler) {
+	if !instr.forward {
+		return
+	}
 
-	case mnemonics.JLE:
-		a.JumpIfLessOrEqual(instr.Label)
+	address, exists := a.Labels[instr.Label]
 
-	case mnemonics.JMP:
-		a.Jump(instr.Label)
+	if !exists {
+		panic(fmt.Errorf("Unknown label '%s'", instr.Label))
 	}
 
-	instr.size = byte(a.Len() - start)
+	offset := int32(address) - int32(instr.pointer+4)
+	binary.LittleEndian.PutUint32(a.Bytes()[instr.pointer:], uint32(offset))
 }
 
 // String implements the string serialization.
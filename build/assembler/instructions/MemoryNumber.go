@@ -14,7 +14,7 @@ type MemoryNumber struct {
 	Destination *register.Register
 	Number      uint64
 	UsedBy      string
-	Offset      byte
+	Offset      int32
 	ByteCount   byte
 }
 
@@ -24,7 +24,7 @@ func (instr *MemoryNumber) Exec(a *asm.Assembler) {
 
 	switch instr.Mnemonic {
 	case mnemonics.STORE:
-		a.StoreNumber(instr.Destination.Name, instr.Offset, instr.ByteCount, instr.Number)
+		encodeStoreNumber(a, instr.Destination.Name, instr.Offset, instr.ByteCount, instr.Number)
 
 	default:
 		panic("This should never happen!")
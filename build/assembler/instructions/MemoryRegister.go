@@ -15,7 +15,7 @@ type MemoryRegister struct {
 	Source      *register.Register
 	UsedBy1     string
 	UsedBy2     string
-	Offset      byte
+	Offset      int32
 	ByteCount   byte
 }
 
@@ -25,7 +25,12 @@ func (instr *MemoryRegister) Exec(a *asm.Assembler) {
 
 	switch instr.Mnemonic {
 	case mnemonics.STORE:
-		a.StoreRegister(instr.Destination.Name, instr.Offset, instr.ByteCount, instr.Source.Name)
+		if instr.Source.IsFloat() {
+			encodeFloatStore(a, instr.Destination.Name, instr.Offset, instr.ByteCount, instr.Source.Name)
+			break
+		}
+
+		encodeStoreRegister(a, instr.Destination.Name, instr.Offset, instr.ByteCount, instr.Source.Name)
 
 	default:
 		panic("This should never happen!")
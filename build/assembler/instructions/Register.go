@@ -29,14 +29,34 @@ func (instr *Register) Exec(a *asm.Assembler) {
 	case mnemonics.DIV:
 		a.DivRegister(instr.Destination.Name)
 
+	case mnemonics.UDIV:
+		encodeRegister(a, 0xF7, 6, instr.Destination.Name)
+
 	case mnemonics.CDQ:
 		a.SignExtendToDX(instr.Destination.Name)
 
 	case mnemonics.PUSH:
+		if instr.Destination.IsFloat() {
+			encodePushFloat(a, instr.Destination.Name)
+			break
+		}
+
 		a.PushRegister(instr.Destination.Name)
 
 	case mnemonics.POP:
+		if instr.Destination.IsFloat() {
+			encodePopFloat(a, instr.Destination.Name)
+			break
+		}
+
 		a.PopRegister(instr.Destination.Name)
+
+	default:
+		code, isSet := setCodes[instr.Mnemonic]
+
+		if isSet {
+			encodeSet(a, code, instr.Destination.Name)
+		}
 	}
 
 	instr.size = byte(a.Len() - start)
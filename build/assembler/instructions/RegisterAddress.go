@@ -24,6 +24,12 @@ func (instr *RegisterAddress) Exec(a *asm.Assembler) {
 	switch instr.Mnemonic {
 	case mnemonics.MOV:
 		a.MoveRegisterAddress(instr.Destination.Name, instr.Address)
+
+	case mnemonics.MOVSD:
+		encodeFloatLoadAddress(a, instr.Destination.Name, instr.Address, 8)
+
+	case mnemonics.MOVSS:
+		encodeFloatLoadAddress(a, instr.Destination.Name, instr.Address, 4)
 	}
 
 	instr.size = byte(a.Len() - start)
@@ -15,7 +15,7 @@ type RegisterMemory struct {
 	Source      *register.Register
 	UsedBy1     string
 	UsedBy2     string
-	Offset      byte
+	Offset      int32
 	ByteCount   byte
 }
 
@@ -25,7 +25,15 @@ func (instr *RegisterMemory) Exec(a *asm.Assembler) {
 
 	switch instr.Mnemonic {
 	case mnemonics.LOAD:
-		a.LoadRegister(instr.Destination.Name, instr.Source.Name, instr.Offset, instr.ByteCount)
+		if instr.Destination.IsFloat() {
+			encodeFloatLoad(a, instr.Destination.Name, instr.Source.Name, instr.Offset, instr.ByteCount)
+			break
+		}
+
+		encodeLoad(a, instr.Destination.Name, instr.Source.Name, instr.Offset, instr.ByteCount)
+
+	case mnemonics.LOADU:
+		encodeLoadUnsigned(a, instr.Destination.Name, instr.Source.Name, instr.Offset, instr.ByteCount)
 
 	default:
 		panic("This should never happen!")
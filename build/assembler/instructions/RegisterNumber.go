@@ -2,6 +2,7 @@ package instructions
 
 import (
 	"fmt"
+	"math"
 
 	"github.com/akyoto/asm"
 	"github.com/akyoto/q/build/assembler/mnemonics"
@@ -22,19 +23,52 @@ func (instr *RegisterNumber) Exec(a *asm.Assembler) {
 
 	switch instr.Mnemonic {
 	case mnemonics.MOV:
+		number := int64(instr.Number)
+
+		// Negative numbers need to be sign-extended to 64 bits.
+		if number < 0 && number >= math.MinInt32 {
+			encodeRegister(a, 0xC7, 0, instr.Destination.Name)
+			a.WriteUint32(uint32(instr.Number))
+			break
+		}
+
 		a.MoveRegisterNumber(instr.Destination.Name, instr.Number)
 
 	case mnemonics.CMP:
 		a.CompareRegisterNumber(instr.Destination.Name, instr.Number)
 
 	case mnemonics.ADD:
-		a.AddRegisterNumber(instr.Destination.Name, instr.Number)
+		encodeRegisterNumber(a, 0, instr.Destination.Name, int64(instr.Number))
 
 	case mnemonics.MUL:
-		a.MulRegisterNumber(instr.Destination.Name, instr.Number)
+		encodeMulNumber(a, instr.Destination.Name, int64(instr.Number))
 
 	case mnemonics.SUB:
-		a.SubRegisterNumber(instr.Destination.Name, instr.Number)
+		encodeRegisterNumber(a, 5, instr.Destination.Name, int64(instr.Number))
+
+	case mnemonics.AND:
+		encodeRegister(a, 0x81, 4, instr.Destination.Name)
+		a.WriteUint32(uint32(instr.Number))
+
+	case mnemonics.OR:
+		encodeRegister(a, 0x81, 1, instr.Destination.Name)
+		a.WriteUint32(uint32(instr.Number))
+
+	case mnemonics.XOR:
+		encodeRegister(a, 0x81, 6, instr.Destination.Name)
+		a.WriteUint32(uint32(instr.Number))
+
+	case mnemonics.SHL:
+		encodeRegister(a, 0xC1, 4, instr.Destination.Name)
+		a.WriteBytes(byte(instr.Number))
+
+	case mnemonics.SHR:
+		encodeRegister(a, 0xC1, 5, instr.Destination.Name)
+		a.WriteBytes(byte(instr.Number))
+
+	case mnemonics.SAR:
+		encodeRegister(a, 0xC1, 7, instr.Destination.Name)
+		a.WriteBytes(byte(instr.Number))
 	}
 
 	instr.size = byte(a.Len() - start)
@@ -35,7 +35,60 @@ func (instr *RegisterRegister) Exec(a *asm.Assembler) {
 		a.SubRegisterRegister(instr.Destination.Name, instr.Source.Name)
 
 	case mnemonics.MUL:
-		a.MulRegisterRegister(instr.Destination.Name, instr.Source.Name)
+		encodeMul(a, instr.Destination.Name, instr.Source.Name)
+
+	case mnemonics.AND:
+		encodeRegisterRegister(a, 0x21, instr.Destination.Name, instr.Source.Name)
+
+	case mnemonics.OR:
+		encodeRegisterRegister(a, 0x09, instr.Destination.Name, instr.Source.Name)
+
+	case mnemonics.XOR:
+		encodeRegisterRegister(a, 0x31, instr.Destination.Name, instr.Source.Name)
+
+	// Shifts by a register always use the lowest byte of rcx as the bit count.
+	case mnemonics.SHL:
+		encodeRegister(a, 0xD3, 4, instr.Destination.Name)
+
+	case mnemonics.SHR:
+		encodeRegister(a, 0xD3, 5, instr.Destination.Name)
+
+	case mnemonics.SAR:
+		encodeRegister(a, 0xD3, 7, instr.Destination.Name)
+
+	case mnemonics.MOVSXB:
+		encodeExtend(a, []byte{0x0F, 0xBE}, 1, instr.Destination.Name, instr.Source.Name)
+
+	case mnemonics.MOVSXW:
+		encodeExtend(a, []byte{0x0F, 0xBF}, 1, instr.Destination.Name, instr.Source.Name)
+
+	case mnemonics.MOVSXD:
+		encodeExtend(a, []byte{0x63}, 1, instr.Destination.Name, instr.Source.Name)
+
+	case mnemonics.MOVZXB:
+		encodeExtend(a, []byte{0x0F, 0xB6}, 1, instr.Destination.Name, instr.Source.Name)
+
+	case mnemonics.MOVZXW:
+		encodeExtend(a, []byte{0x0F, 0xB7}, 1, instr.Destination.Name, instr.Source.Name)
+
+	// Writing a 32-bit register clears the upper half of the 64-bit register.
+	case mnemonics.MOVZXD:
+		encodeExtend(a, []byte{0x8B}, 0, instr.Destination.Name, instr.Source.Name)
+
+	// movq transfers the bits between a general purpose and a floating-point register.
+	case mnemonics.MOVQ:
+		if instr.Destination.IsFloat() {
+			encodeFloat(a, 0x66, 1, 0x6E, instr.Destination.Name, instr.Source.Name)
+		} else {
+			encodeFloat(a, 0x66, 1, 0x7E, instr.Source.Name, instr.Destination.Name)
+		}
+
+	default:
+		code, isFloat := floatCodes[instr.Mnemonic]
+
+		if isFloat {
+			encodeFloat(a, code.prefix, code.w, code.code, instr.Destination.Name, instr.Source.Name)
+		}
 	}
 
 	instr.size = byte(a.Len() - start)
@@ -0,0 +1,402 @@
+package instructions
+
+import (
+	"math"
+
+	"github.com/akyoto/asm"
+	"github.com/akyoto/asm/opcode"
+	"github.com/akyoto/asm/sections"
+	"github.com/akyoto/q/build/assembler/mnemonics"
+)
+
+// registerCodes maps the 64-bit and floating-point register names to their machine code representation.
+var registerCodes = map[string]byte{
+	"rax": 0,
+	"rcx": 1,
+	"rdx": 2,
+	"rbx": 3,
+	"rsp": 4,
+	"rbp": 5,
+	"rsi": 6,
+	"rdi": 7,
+	"r8":  8,
+	"r9":  9,
+	"r10": 10,
+	"r11": 11,
+	"r12": 12,
+	"r13": 13,
+	"r14": 14,
+	"r15": 15,
+
+	"xmm0":  0,
+	"xmm1":  1,
+	"xmm2":  2,
+	"xmm3":  3,
+	"xmm4":  4,
+	"xmm5":  5,
+	"xmm6":  6,
+	"xmm7":  7,
+	"xmm8":  8,
+	"xmm9":  9,
+	"xmm10": 10,
+	"xmm11": 11,
+	"xmm12": 12,
+	"xmm13": 13,
+	"xmm14": 14,
+	"xmm15": 15,
+}
+
+// encodeRegisterRegister encodes a 64-bit instruction with the destination
+// in the rm field and the source in the reg field of the ModRM byte.
+func encodeRegisterRegister(a *asm.Assembler, code byte, destination string, source string) {
+	to := registerCodes[destination]
+	from := registerCodes[source]
+	a.WriteBytes(opcode.REX(1, from>>3, 0, to>>3), code, opcode.ModRM(0b11, from&0b111, to&0b111))
+}
+
+// encodeMul encodes a signed multiplication of the destination with the source register.
+// Unlike most instructions, imul expects the destination in the reg field of the ModRM byte.
+func encodeMul(a *asm.Assembler, destination string, source string) {
+	to := registerCodes[destination]
+	from := registerCodes[source]
+	a.WriteBytes(opcode.REX(1, to>>3, 0, from>>3), 0x0F, 0xAF, opcode.ModRM(0b11, to&0b111, from&0b111))
+}
+
+// encodeExtend encodes a sign or zero extension of the lowest bytes of the source register.
+// Like imul, it expects the destination in the reg field of the ModRM byte.
+// A REX prefix is needed to access the lowest byte of rsp, rbp, rsi and rdi.
+func encodeExtend(a *asm.Assembler, code []byte, w byte, destination string, source string) {
+	to := registerCodes[destination]
+	from := registerCodes[source]
+
+	if w != 0 || to >= 8 || from >= 8 {
+		a.WriteBytes(opcode.REX(w, to>>3, 0, from>>3))
+	}
+
+	_, _ = a.Write(code)
+	a.WriteBytes(opcode.ModRM(0b11, to&0b111, from&0b111))
+}
+
+// setCodes maps the conditional set mnemonics to their opcode after the 0x0F escape byte.
+var setCodes = map[string]byte{
+	mnemonics.SETE:  0x94,
+	mnemonics.SETNE: 0x95,
+	mnemonics.SETL:  0x9C,
+	mnemonics.SETLE: 0x9E,
+	mnemonics.SETG:  0x9F,
+	mnemonics.SETGE: 0x9D,
+	mnemonics.SETB:  0x92,
+	mnemonics.SETBE: 0x96,
+	mnemonics.SETA:  0x97,
+	mnemonics.SETAE: 0x93,
+}
+
+// encodeSet encodes a conditional set of the lowest byte of the register.
+// The REX prefix is always needed to access the lowest byte of rsp, rbp, rsi and rdi.
+func encodeSet(a *asm.Assembler, code byte, destination string) {
+	to := registerCodes[destination]
+	a.WriteBytes(opcode.REX(0, 0, 0, to>>3), 0x0F, code, opcode.ModRM(0b11, 0, to&0b111))
+}
+
+// encodeMulNumber encodes a signed multiplication of the destination with a 32-bit number.
+func encodeMulNumber(a *asm.Assembler, destination string, number int64) {
+	to := registerCodes[destination]
+	rex := opcode.REX(1, to>>3, 0, to>>3)
+	modRM := opcode.ModRM(0b11, to&0b111, to&0b111)
+
+	if number >= math.MinInt8 && number <= math.MaxInt8 {
+		a.WriteBytes(rex, 0x6B, modRM, byte(number))
+		return
+	}
+
+	a.WriteBytes(rex, 0x69, modRM)
+	a.WriteUint32(uint32(number))
+}
+
+// encodeRegister encodes a 64-bit instruction with a single register
+// operand and an opcode extension in the reg field of the ModRM byte.
+func encodeRegister(a *asm.Assembler, code byte, extension byte, destination string) {
+	to := registerCodes[destination]
+	a.WriteBytes(opcode.REX(1, 0, 0, to>>3), code, opcode.ModRM(0b11, extension, to&0b111))
+}
+
+// encodeRegisterNumber encodes an arithmetic instruction with a register and a number operand.
+// Numbers that fit into a single byte use the shorter sign-extended 8-bit encoding,
+// all other numbers are encoded as sign-extended 32-bit values.
+func encodeRegisterNumber(a *asm.Assembler, extension byte, destination string, number int64) {
+	if number >= math.MinInt8 && number <= math.MaxInt8 {
+		encodeRegister(a, 0x83, extension, destination)
+		a.WriteBytes(byte(number))
+		return
+	}
+
+	encodeRegister(a, 0x81, extension, destination)
+	a.WriteUint32(uint32(number))
+}
+
+// encodeMemory encodes an instruction accessing the memory address stored in the base register
+// plus the offset. The reg operand is either a register code or an opcode extension.
+func encodeMemory(a *asm.Assembler, code []byte, w byte, reg byte, base string, offset int32, forceREX bool) {
+	address := registerCodes[base]
+
+	if w != 0 || reg >= 8 || address >= 8 || forceREX {
+		a.WriteBytes(opcode.REX(w, reg>>3, 0, address>>3))
+	}
+
+	_, _ = a.Write(code)
+
+	// rbp and r13 can't be encoded without an offset
+	mod := byte(0b10)
+
+	switch {
+	case offset == 0 && address&0b111 != 0b101:
+		mod = 0b00
+
+	case offset >= -128 && offset <= 127:
+		mod = 0b01
+	}
+
+	a.WriteBytes(opcode.ModRM(mod, reg&0b111, address&0b111))
+
+	// rsp and r12 always need an SIB byte
+	if address&0b111 == 0b100 {
+		a.WriteBytes(opcode.SIB(0b00, 0b100, 0b100))
+	}
+
+	switch mod {
+	case 0b01:
+		a.WriteBytes(byte(offset))
+
+	case 0b10:
+		a.WriteUint32(uint32(offset))
+	}
+}
+
+// encodeLoad loads the value at the memory address into the destination register.
+// Values with less than 8 bytes are sign-extended to the full register size.
+func encodeLoad(a *asm.Assembler, destination string, base string, offset int32, byteCount byte) {
+	to := registerCodes[destination]
+
+	switch byteCount {
+	case 8:
+		encodeMemory(a, []byte{0x8B}, 1, to, base, offset, false)
+
+	case 4:
+		encodeMemory(a, []byte{0x63}, 1, to, base, offset, false)
+
+	case 2:
+		encodeMemory(a, []byte{0x0F, 0xBF}, 1, to, base, offset, false)
+
+	case 1:
+		encodeMemory(a, []byte{0x0F, 0xBE}, 1, to, base, offset, false)
+	}
+}
+
+// encodeLoadUnsigned loads the value at the memory address into the destination register.
+// Values with less than 8 bytes are zero-extended to the full register size.
+func encodeLoadUnsigned(a *asm.Assembler, destination string, base string, offset int32, byteCount byte) {
+	to := registerCodes[destination]
+
+	switch byteCount {
+	case 8:
+		encodeMemory(a, []byte{0x8B}, 1, to, base, offset, false)
+
+	case 4:
+		encodeMemory(a, []byte{0x8B}, 0, to, base, offset, false)
+
+	case 2:
+		encodeMemory(a, []byte{0x0F, 0xB7}, 1, to, base, offset, false)
+
+	case 1:
+		encodeMemory(a, []byte{0x0F, 0xB6}, 1, to, base, offset, false)
+	}
+}
+
+// encodeStoreRegister stores the lowest bytes of the source register at the memory address.
+func encodeStoreRegister(a *asm.Assembler, base string, offset int32, byteCount byte, source string) {
+	from := registerCodes[source]
+
+	switch byteCount {
+	case 8:
+		encodeMemory(a, []byte{0x89}, 1, from, base, offset, false)
+
+	case 4:
+		encodeMemory(a, []byte{0x89}, 0, from, base, offset, false)
+
+	case 2:
+		a.WriteBytes(0x66)
+		encodeMemory(a, []byte{0x89}, 0, from, base, offset, false)
+
+	case 1:
+		// The lowest bytes of rsp, rbp, rsi and rdi require a REX prefix.
+		encodeMemory(a, []byte{0x88}, 0, from, base, offset, from >= 4 && from < 8)
+	}
+}
+
+// encodeStoreNumber stores a number with the given size at the memory address.
+func encodeStoreNumber(a *asm.Assembler, base string, offset int32, byteCount byte, number uint64) {
+	switch byteCount {
+	case 8:
+		encodeMemory(a, []byte{0xC7}, 1, 0, base, offset, false)
+		a.WriteUint32(uint32(number))
+
+	case 4:
+		encodeMemory(a, []byte{0xC7}, 0, 0, base, offset, false)
+		a.WriteUint32(uint32(number))
+
+	case 2:
+		a.WriteBytes(0x66)
+		encodeMemory(a, []byte{0xC7}, 0, 0, base, offset, false)
+		a.WriteUint16(uint16(number))
+
+	case 1:
+		encodeMemory(a, []byte{0xC6}, 0, 0, base, offset, false)
+		a.WriteBytes(byte(number))
+	}
+}
+
+// floatCodes maps the floating-point mnemonics to their mandatory prefix, their opcode
+// after the 0x0F escape byte and the REX.W bit for 64-bit general purpose operands.
+// A prefix of 0 means that the instruction has no prefix.
+var floatCodes = map[string]struct {
+	prefix byte
+	code   byte
+	w      byte
+}{
+	mnemonics.MOVSD:     {0xF2, 0x10, 0},
+	mnemonics.ADDSD:     {0xF2, 0x58, 0},
+	mnemonics.ADDSS:     {0xF3, 0x58, 0},
+	mnemonics.SUBSD:     {0xF2, 0x5C, 0},
+	mnemonics.SUBSS:     {0xF3, 0x5C, 0},
+	mnemonics.MULSD:     {0xF2, 0x59, 0},
+	mnemonics.MULSS:     {0xF3, 0x59, 0},
+	mnemonics.DIVSD:     {0xF2, 0x5E, 0},
+	mnemonics.DIVSS:     {0xF3, 0x5E, 0},
+	mnemonics.UCOMISD:   {0x66, 0x2E, 0},
+	mnemonics.UCOMISS:   {0, 0x2E, 0},
+	mnemonics.CVTSS2SD:  {0xF3, 0x5A, 0},
+	mnemonics.CVTSD2SS:  {0xF2, 0x5A, 0},
+	mnemonics.CVTSI2SD:  {0xF2, 0x2A, 1},
+	mnemonics.CVTSI2SS:  {0xF3, 0x2A, 1},
+	mnemonics.CVTTSD2SI: {0xF2, 0x2C, 1},
+	mnemonics.CVTTSS2SI: {0xF3, 0x2C, 1},
+}
+
+// encodeFloat encodes an SSE instruction with the first operand in the reg field
+// and the second operand in the rm field of the ModRM byte.
+// The mandatory prefix needs to be written before the REX prefix.
+func encodeFloat(a *asm.Assembler, prefix byte, w byte, code byte, reg string, rm string) {
+	first := registerCodes[reg]
+	second := registerCodes[rm]
+
+	if prefix != 0 {
+		a.WriteBytes(prefix)
+	}
+
+	if w != 0 || first >= 8 || second >= 8 {
+		a.WriteBytes(opcode.REX(w, first>>3, 0, second>>3))
+	}
+
+	a.WriteBytes(0x0F, code, opcode.ModRM(0b11, first&0b111, second&0b111))
+}
+
+// encodeFloatLoad loads a floating-point number with the given size from the memory address.
+func encodeFloatLoad(a *asm.Assembler, destination string, base string, offset int32, byteCount byte) {
+	a.WriteBytes(floatPrefix(byteCount))
+	encodeMemory(a, []byte{0x0F, 0x10}, 0, registerCodes[destination], base, offset, false)
+}
+
+// encodeFloatStore stores a floating-point number with the given size at the memory address.
+func encodeFloatStore(a *asm.Assembler, base string, offset int32, byteCount byte, source string) {
+	a.WriteBytes(floatPrefix(byteCount))
+	encodeMemory(a, []byte{0x0F, 0x11}, 0, registerCodes[source], base, offset, false)
+}
+
+// encodeFloatLoadAddress loads a floating-point number from an address in the data section.
+// The address is encoded as an absolute 32-bit displacement that is fixed when the sections are known.
+func encodeFloatLoadAddress(a *asm.Assembler, destination string, address uint32, byteCount byte) {
+	to := registerCodes[destination]
+	a.WriteBytes(floatPrefix(byteCount))
+
+	if to >= 8 {
+		a.WriteBytes(opcode.REX(0, to>>3, 0, 0))
+	}
+
+	a.WriteBytes(0x0F, 0x10, opcode.ModRM(0b00, to&0b111, 0b100), opcode.SIB(0b00, 0b100, 0b101))
+
+	a.StringPointers = append(a.StringPointers, sections.Pointer{
+		Address:  address,
+		Position: a.Len(),
+	})
+
+	a.WriteUint32(address)
+}
+
+// encodePushFloat saves a floating-point register on the stack.
+// There is no push instruction for these registers,
+// therefore the stack pointer is decreased manually.
+func encodePushFloat(a *asm.Assembler, source string) {
+	encodeRegister(a, 0x83, 5, "rsp")
+	a.WriteBytes(8)
+	encodeFloatStore(a, "rsp", 0, 8, source)
+}
+
+// encodePopFloat restores a floating-point register from the stack.
+func encodePopFloat(a *asm.Assembler, destination string) {
+	encodeFloatLoad(a, destination, "rsp", 0, 8)
+	encodeRegister(a, 0x83, 0, "rsp")
+	a.WriteBytes(8)
+}
+
+// floatPrefix returns the prefix for double precision (8 bytes) or single precision (4 bytes).
+func floatPrefix(byteCount byte) byte {
+	if byteCount == 4 {
+		return 0xF3
+	}
+
+	return 0xF2
+}
+
+// jumpCodes maps the jump mnemonics to their short (8-bit) and near (32-bit) opcodes.
+var jumpCodes = map[string]struct {
+	short byte
+	near  []byte
+}{
+	mnemonics.JMP: {0xeb, []byte{0xe9}},
+	mnemonics.JE:  {0x74, []byte{0x0f, 0x84}},
+	mnemonics.JNE: {0x75, []byte{0x0f, 0x85}},
+	mnemonics.JL:  {0x7c, []byte{0x0f, 0x8c}},
+	mnemonics.JLE: {0x7e, []byte{0x0f, 0x8e}},
+	mnemonics.JG:  {0x7f, []byte{0x0f, 0x8f}},
+	mnemonics.JGE: {0x7d, []byte{0x0f, 0x8d}},
+	mnemonics.JAE: {0x73, []byte{0x0f, 0x83}},
+	mnemonics.JA:  {0x77, []byte{0x0f, 0x87}},
+	mnemonics.JB:  {0x72, []byte{0x0f, 0x82}},
+	mnemonics.JBE: {0x76, []byte{0x0f, 0x86}},
+}
+
+// encodeJump encodes a jump to the label. Backward jumps use the shortest encoding.
+// Forward jumps always use a 32-bit offset because the distance is not known yet,
+// the returned position of the offset needs to be fixed when the label exists.
+func encodeJump(a *asm.Assembler, mnemonic string, label string) (uint32, bool) {
+	codes := jumpCodes[mnemonic]
+	address, exists := a.Labels[label]
+
+	if !exists {
+		_, _ = a.Write(codes.near)
+		position := a.Len()
+		a.WriteUint32(0)
+		return position, true
+	}
+
+	offset := int32(address) - int32(a.Len()+2)
+
+	if offset >= math.MinInt8 && offset <= math.MaxInt8 {
+		a.WriteBytes(codes.short, byte(offset))
+		return 0, false
+	}
+
+	_, _ = a.Write(codes.near)
+	a.WriteUint32(uint32(int32(address) - int32(a.Len()+4)))
+	return 0, false
+}
@@ -7,6 +7,7 @@ const (
 	SUB     = "sub"
 	MUL     = "imul"
 	DIV     = "idiv"
+	UDIV    = "div"
 	CDQ     = "cdq"
 	RET     = "ret"
 	SYSCALL = "syscall"
@@ -18,13 +19,65 @@ const (
 	JLE     = "jle"
 	JG      = "jg"
 	JGE     = "jge"
+	JAE     = "jae"
+	JA      = "ja"
+	JB      = "jb"
+	JBE     = "jbe"
 	INC     = "inc"
 	DEC     = "dec"
 	PUSH    = "push"
 	POP     = "pop"
 	CPUID   = "cpuid"
+	AND     = "and"
+	OR      = "or"
+	XOR     = "xor"
+	SHL     = "shl"
+	SAR     = "sar"
+	SHR     = "shr"
+
+	// Set the lowest byte to 1 if the condition is true, otherwise 0
+	SETE  = "sete"
+	SETNE = "setne"
+	SETL  = "setl"
+	SETLE = "setle"
+	SETG  = "setg"
+	SETGE = "setge"
+	SETB  = "setb"
+	SETBE = "setbe"
+	SETA  = "seta"
+	SETAE = "setae"
+
+	// Sign and zero extension of the lowest bytes
+	MOVSXB = "movsxb"
+	MOVSXW = "movsxw"
+	MOVSXD = "movsxd"
+	MOVZXB = "movzxb"
+	MOVZXW = "movzxw"
+	MOVZXD = "movzxd"
+
+	// Floating-point
+	MOVSD     = "movsd"
+	MOVSS     = "movss"
+	MOVQ      = "movq"
+	ADDSD     = "addsd"
+	ADDSS     = "addss"
+	SUBSD     = "subsd"
+	SUBSS     = "subss"
+	MULSD     = "mulsd"
+	MULSS     = "mulss"
+	DIVSD     = "divsd"
+	DIVSS     = "divss"
+	UCOMISD   = "ucomisd"
+	UCOMISS   = "ucomiss"
+	CVTSI2SD  = "cvtsi2sd"
+	CVTSI2SS  = "cvtsi2ss"
+	CVTTSD2SI = "cvttsd2si"
+	CVTTSS2SI = "cvttss2si"
+	CVTSS2SD  = "cvtss2sd"
+	CVTSD2SS  = "cvtsd2ss"
 
 	// Artificial
 	STORE = "store"
 	LOAD  = "load"
+	LOADU = "loadu"
 )
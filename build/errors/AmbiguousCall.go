@@ -0,0 +1,16 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AmbiguousCall represents calls that match more than one overload of a function.
+type AmbiguousCall struct {
+	Name       string
+	Candidates []string
+}
+
+func (err *AmbiguousCall) Error() string {
+	return fmt.Sprintf("Ambiguous call of '%s' could refer to '%s'", err.Name, strings.Join(err.Candidates, "' or '"))
+}
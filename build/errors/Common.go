@@ -1,15 +1,26 @@
 package errors
 
 var (
+	ArrayAssignment             = &simple{"Arrays can only be modified element by element", false}
+	ConstantAssignment          = &simple{"Constants can not be modified", false}
+	ConstantCycle               = &simple{"Constant depends on itself", false}
+	DivisionByZero              = &simple{"Division by zero", false}
 	ExceededMaxParameters       = &simple{"Exceeded maximum number of parameters per function", false}
+	ExceededMaxReturnValues     = &simple{"Exceeded maximum number of return values per function", false}
 	ExceededMaxVariables        = &simple{"Exceeded maximum limit of variables per function", false}
 	ExpectedVariable            = &simple{"Expected variable on the left side of the assignment", false}
+	ImmutableReceiver           = &simple{"The struct can only be modified in methods declared with 'mut'", false}
+	InstructionOutsideCase      = &simple{"Instructions inside of 'match' need to be in a case block", false}
 	InvalidExpression           = &simple{"Invalid expression", false}
 	InvalidFunctionName         = &simple{"A function can not be named 'func' or 'fn'", false}
 	InvalidInstruction          = &simple{"Invalid instruction", false}
 	MissingAssignmentOperator   = &simple{"Missing assignment operator", false}
 	MissingAssignmentExpression = &simple{"Missing assignment expression", false}
+	MissingConstantName         = &simple{"Missing constant name", false}
+	MissingEnumName             = &simple{"Missing enum name", false}
 	MissingEndingNewline        = &simple{"Missing newline at the end of the file", false}
+	MissingInterpolationValue   = &simple{"Missing value in braces", false}
+	MissingFieldName            = &simple{"Missing field name in struct literal", false}
 	MissingFunctionName         = &simple{"Expected function name before '('", false}
 	MissingParameter            = &simple{"Missing parameter", false}
 	MissingRange                = &simple{"Missing range expression in for loop", false}
@@ -17,10 +28,21 @@ var (
 	MissingRangeLimit           = &simple{"Missing upper limit in range expression", true}
 	MissingReturnType           = &simple{"Missing function return type", false}
 	MissingStructName           = &simple{"Missing struct name", false}
+	MutableFunction             = &simple{"Only methods can be declared with 'mut'", false}
+	NotAnArray                  = &simple{"Only arrays can be indexed", false}
+	NotAPointer                 = &simple{"Only pointers can be dereferenced", false}
+	NotAddressable              = &simple{"Only structs, struct fields and array elements have an address", false}
 	NotImplemented              = &simple{"Not implemented", false}
 	ParameterOpeningBracket     = &simple{"Missing opening bracket '(' after the function name", false}
+	StructAssignment            = &simple{"Structs can only be modified field by field", false}
+	StructCycle                 = &simple{"Struct contains itself", false}
+	TextAssignment              = &simple{"Texts can not be modified", false}
 	ReturnWithoutFunctionType   = &simple{"Returning a value in a function without a return type", false}
 	EnsureWithoutFunctionType   = &simple{"Ensuring a value in a function without a return type", false}
+	BreakOutsideLoop            = &simple{"'break' can only be used inside a loop", false}
+	ContinueOutsideLoop         = &simple{"'continue' can only be used inside a loop", false}
+	ElseWithoutIf               = &simple{"Expected 'if' block before 'else'", false}
 	TopLevel                    = &simple{"Only function definitions are allowed at the top level", false}
 	UnnecessaryNewlines         = &simple{"More than 2 successive empty lines", false}
+	UnreachableCase             = &simple{"Cases after the default case '_' can never be reached", false}
 )
@@ -0,0 +1,12 @@
+package errors
+
+import "fmt"
+
+// ConstantAlreadyExists represents an error where a constant with the same name has already been defined.
+type ConstantAlreadyExists struct {
+	Name string
+}
+
+func (err *ConstantAlreadyExists) Error() string {
+	return fmt.Sprintf("Constant '%s' already exists", err.Name)
+}
@@ -0,0 +1,12 @@
+package errors
+
+import "fmt"
+
+// DuplicateCase represents an enum or match case that has been listed more than once.
+type DuplicateCase struct {
+	Name string
+}
+
+func (err *DuplicateCase) Error() string {
+	return fmt.Sprintf("Case '%s' is listed more than once", err.Name)
+}
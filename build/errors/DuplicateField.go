@@ -0,0 +1,12 @@
+package errors
+
+import "fmt"
+
+// DuplicateField represents a field that has been initialized more than once in a struct literal.
+type DuplicateField struct {
+	Name string
+}
+
+func (err *DuplicateField) Error() string {
+	return fmt.Sprintf("Field '%s' is initialized more than once", err.Name)
+}
@@ -0,0 +1,12 @@
+package errors
+
+import "fmt"
+
+// FunctionAlreadyExists represents an error where a function with the same parameter types has already been defined.
+type FunctionAlreadyExists struct {
+	Name string
+}
+
+func (err *FunctionAlreadyExists) Error() string {
+	return fmt.Sprintf("Function '%s' already exists", err.Name)
+}
@@ -0,0 +1,13 @@
+package errors
+
+import "fmt"
+
+// IndexOutOfBounds represents a constant array index outside of the array.
+type IndexOutOfBounds struct {
+	Index  int64
+	Length uint
+}
+
+func (err *IndexOutOfBounds) Error() string {
+	return fmt.Sprintf("Index %d is out of bounds for an array of length %d", err.Index, err.Length)
+}
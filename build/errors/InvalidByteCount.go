@@ -0,0 +1,12 @@
+package errors
+
+import "fmt"
+
+// InvalidByteCount represents a memory access with an unsupported number of bytes.
+type InvalidByteCount struct {
+	ByteCount string
+}
+
+func (err *InvalidByteCount) Error() string {
+	return fmt.Sprintf("Invalid byte count '%s', expected 1, 2, 4 or 8", err.ByteCount)
+}
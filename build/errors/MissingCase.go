@@ -0,0 +1,12 @@
+package errors
+
+import "fmt"
+
+// MissingCase represents an enum case that is not handled by a match.
+type MissingCase struct {
+	Name string
+}
+
+func (err *MissingCase) Error() string {
+	return fmt.Sprintf("Case '%s' is not handled", err.Name)
+}
@@ -0,0 +1,13 @@
+package errors
+
+import "fmt"
+
+// NumberOutOfRange represents a number literal that doesn't fit into its type.
+type NumberOutOfRange struct {
+	Number string
+	Type   string
+}
+
+func (err *NumberOutOfRange) Error() string {
+	return fmt.Sprintf("Number %s is out of range for type '%s'", err.Number, err.Type)
+}
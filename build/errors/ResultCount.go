@@ -0,0 +1,15 @@
+package errors
+
+import "fmt"
+
+// ResultCount represents an error where the number of assigned variables
+// is different from the number of values returned by the function.
+type ResultCount struct {
+	FunctionName  string
+	CountGiven    int
+	CountReturned int
+}
+
+func (err *ResultCount) Error() string {
+	return fmt.Sprintf("'%s' returns %d values but %d variables were given", err.FunctionName, err.CountReturned, err.CountGiven)
+}
@@ -0,0 +1,23 @@
+package errors
+
+import "fmt"
+
+// ReturnCount represents an error where the number of values in a return statement
+// is different from the number of return types of the function.
+type ReturnCount struct {
+	FunctionName  string
+	CountGiven    int
+	CountRequired int
+}
+
+func (err *ReturnCount) Error() string {
+	if err.CountGiven < err.CountRequired {
+		return fmt.Sprintf("Too few return values in '%s'", err.FunctionName)
+	}
+
+	if err.CountGiven > err.CountRequired {
+		return fmt.Sprintf("Too many return values in '%s'", err.FunctionName)
+	}
+
+	return ""
+}
@@ -0,0 +1,18 @@
+package errors
+
+import "fmt"
+
+// UnknownCase represents unknown enum cases.
+type UnknownCase struct {
+	TypeName    string
+	Name        string
+	CorrectName string
+}
+
+func (err *UnknownCase) Error() string {
+	if err.CorrectName != "" {
+		return fmt.Sprintf("Enum '%s' doesn't have the case '%s', did you mean '%s'?", err.TypeName, err.Name, err.CorrectName)
+	}
+
+	return fmt.Sprintf("Enum '%s' doesn't have the case '%s'", err.TypeName, err.Name)
+}
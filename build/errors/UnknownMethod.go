@@ -0,0 +1,13 @@
+package errors
+
+import "fmt"
+
+// UnknownMethod represents calls of methods that don't exist on the type.
+type UnknownMethod struct {
+	TypeName string
+	Name     string
+}
+
+func (err *UnknownMethod) Error() string {
+	return fmt.Sprintf("Type '%s' doesn't have the method '%s'", err.TypeName, err.Name)
+}
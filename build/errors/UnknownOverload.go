@@ -0,0 +1,18 @@
+package errors
+
+import "fmt"
+
+// UnknownOverload represents calls that don't match any overload of a function.
+type UnknownOverload struct {
+	Name        string
+	Arguments   string
+	CorrectName string
+}
+
+func (err *UnknownOverload) Error() string {
+	if err.CorrectName != "" {
+		return fmt.Sprintf("No overload of '%s' accepts (%s), did you mean '%s'?", err.Name, err.Arguments, err.CorrectName)
+	}
+
+	return fmt.Sprintf("No overload of '%s' accepts (%s)", err.Name, err.Arguments)
+}
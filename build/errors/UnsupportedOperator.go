@@ -0,0 +1,13 @@
+package errors
+
+import "fmt"
+
+// UnsupportedOperator represents operators that can't be used with the type of their operands.
+type UnsupportedOperator struct {
+	Operator string
+	Type     string
+}
+
+func (err *UnsupportedOperator) Error() string {
+	return fmt.Sprintf("Operator '%s' is not supported for type '%s'", err.Operator, err.Type)
+}
@@ -0,0 +1,12 @@
+package errors
+
+import "fmt"
+
+// UnusedConstant represents constants that have never been used.
+type UnusedConstant struct {
+	Name string
+}
+
+func (err *UnusedConstant) Error() string {
+	return fmt.Sprintf("Constant '%s' has never been used", err.Name)
+}
@@ -11,12 +11,13 @@ import (
 
 // Expression is a binary tree with an operator on each node.
 type Expression struct {
-	Token          token.Token
-	Children       []*Expression
-	Parent         *Expression
-	Register       *register.Register
-	Type           *types.Type
-	IsFunctionCall bool
+	Token           token.Token
+	Children        []*Expression
+	Parent          *Expression
+	Register        *register.Register
+	Type            *types.Type
+	IsFunctionCall  bool
+	IsStructLiteral bool
 }
 
 // New creates a new expression.
@@ -66,6 +67,12 @@ func (expr *Expression) EachOperation(callBack func(*Expression) error) error {
 		return callBack(expr)
 	}
 
+	// The operand of the address operator is not evaluated,
+	// the callback needs to calculate its address instead.
+	if expr.IsUnary() && expr.Token.Text() == "&" {
+		return callBack(expr)
+	}
+
 	for _, child := range expr.Children {
 		err := child.EachOperation(callBack)
 
@@ -87,7 +94,7 @@ func (expr *Expression) SortByRegisterCount() {
 		child.SortByRegisterCount()
 	}
 
-	if expr.IsFunctionCall || (expr.Token.Kind == token.Operator && operators.All[string(expr.Token.Bytes)].OperandOrderImportant) {
+	if expr.IsFunctionCall || len(expr.Children) < 2 || (expr.Token.Kind == token.Operator && operators.All[string(expr.Token.Bytes)].OperandOrderImportant) {
 		return
 	}
 
@@ -131,6 +138,11 @@ func (expr *Expression) Replace(other *Expression) {
 	expr.Parent = parent
 }
 
+// IsUnary returns true if the expression is an operation with a single operand like `!a` or `*p`.
+func (expr *Expression) IsUnary() bool {
+	return !expr.IsFunctionCall && expr.Token.Kind == token.Operator && len(expr.Children) == 1
+}
+
 // IsLeaf returns true if the expression is a leaf node with no children.
 func (expr *Expression) IsLeaf() bool {
 	return !expr.IsFunctionCall && len(expr.Children) == 0
@@ -148,6 +160,7 @@ func (expr *Expression) Close() {
 	expr.Register = nil
 	expr.Type = nil
 	expr.IsFunctionCall = false
+	expr.IsStructLiteral = false
 	pool.Put(expr)
 }
 
@@ -168,12 +181,35 @@ func (expr *Expression) write(builder *strings.Builder) {
 	children := expr.Children
 	operator := expr.Token.Text()
 
+	if operator == "[" {
+		builder.WriteByte('(')
+		children[0].write(builder)
+		builder.WriteByte('[')
+		children[1].write(builder)
+		builder.WriteString("])")
+		return
+	}
+
+	if expr.IsUnary() {
+		builder.WriteByte('(')
+		builder.WriteString(operator)
+		children[0].write(builder)
+		builder.WriteByte(')')
+		return
+	}
+
+	start, end := byte('('), byte(')')
+
 	if expr.IsFunctionCall {
 		builder.WriteString(expr.Token.Text())
 		operator = ","
 	}
 
-	builder.WriteByte('(')
+	if expr.IsStructLiteral {
+		start, end = '{', '}'
+	}
+
+	builder.WriteByte(start)
 
 	for index, operand := range children {
 		operand.write(builder)
@@ -183,5 +219,5 @@ func (expr *Expression) write(builder *strings.Builder) {
 		}
 	}
 
-	builder.WriteByte(')')
+	builder.WriteByte(end)
 }
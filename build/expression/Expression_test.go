@@ -46,9 +46,17 @@ func TestExpressionFromTokens(t *testing.T) {
 		{"Operator priority 6", "1+2*3+4*5", "((1+(2*3))+(4*5))"},
 		{"Operator priority 7", "1+2*3*4*5*6", "(1+((((2*3)*4)*5)*6))"},
 		{"Operator priority 8", "1*2*3+4*5*6", "(((1*2)*3)+((4*5)*6))"},
+		{"Operator priority 9", "1+2+3*4/5", "((1+2)+((3*4)/5))"},
+		{"Operator priority 10", "1*2+3<4+5", "(((1*2)+3)<(4+5))"},
+		{"Bitwise operator priority", "1|2&3", "(1|(2&3))"},
+		{"Bitwise operator priority 2", "1<<2+3", "((1<<2)+3)"},
+		{"Bitwise operator priority 3", "1^2%3>>4", "(1^((2%3)>>4))"},
 		{"Complex", "(1+2-3*4)*(5+6-7*8)", "(((1+2)-(3*4))*((5+6)-(7*8)))"},
 		{"Complex 2", "(1+2*3-4)*(5+6*7-8)", "(((1+(2*3))-4)*((5+(6*7))-8))"},
 		{"Complex 3", "(1+2*3-4)*(5+6*7-8)+9-10*11", "(((((1+(2*3))-4)*((5+(6*7))-8))+9)-(10*11))"},
+		{"Struct literal", "Point{}", "Point{}"},
+		{"Struct literal 2", "Point{x: 1, y: 2}", "Point{(x:1),(y:2)}"},
+		{"Struct literal 3", "Line{start: Point{x: 1 + 2}}", "Line{(start:Point{(x:(1+2))})}"},
 		{"Function calls", "a()", "a()"},
 		{"Function calls 2", "a(1)", "a(1)"},
 		{"Function calls 3", "a(1,2)", "a(1,2)"},
@@ -74,6 +82,29 @@ func TestExpressionFromTokens(t *testing.T) {
 		{"Function calls 23", "sum(a,b)*2+15*4", "((sum(a,b)*2)+(15*4))"},
 		{"Package function calls", "math.sum(a,b)", "(math.sum(a,b))"},
 		{"Package function calls 2", "generic.math.sum(a,b)", "((generic.math).sum(a,b))"},
+		{"Field access", "p.x", "(p.x)"},
+		{"Field access 2", "a+p.x/p.y", "(a+((p.x)/(p.y)))"},
+		{"Field access 3", "p.x+p.y*2", "((p.x)+((p.y)*2))"},
+		{"Array access", "a[i]", "(a[i])"},
+		{"Array access 2", "a[i+1]*2", "((a[(i+1)])*2)"},
+		{"Array access 3", "1+a[b[0]]", "(1+(a[(b[0])]))"},
+		{"Array access 4", "p.data[i]", "((p.data)[i])"},
+		{"Array access 5", "f(a[0],a[1])", "f((a[0]),(a[1]))"},
+		{"Negation", "!a", "(!a)"},
+		{"Negation 2", "!a&&b", "((!a)&&b)"},
+		{"Negation 3", "a&&!b", "(a&&(!b))"},
+		{"Negation 4", "!p.x==y", "((!(p.x))==y)"},
+		{"Negation 5", "!f(a)||!(a<b)", "((!f(a))||(!(a<b)))"},
+		{"Negation 6", "!a[i]+1", "((!(a[i]))+1)"},
+		{"Boolean literals", "a==true||false", "((a==true)||false)"},
+		{"Dereference", "*p", "(*p)"},
+		{"Dereference 2", "*p+1", "((*p)+1)"},
+		{"Dereference 3", "a * *p", "(a*(*p))"},
+		{"Dereference 4", "*p.x*2", "((*(p.x))*2)"},
+		{"Dereference 5", "f(*p, *(p+1))", "f((*p),(*(p+1)))"},
+		{"Address", "&p.x", "(&(p.x))"},
+		{"Address 2", "&a[i] + 1", "((&(a[i]))+1)"},
+		{"Address 3", "a & &b", "(a&(&b))"},
 	}
 
 	for _, test := range tests {
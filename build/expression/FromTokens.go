@@ -21,20 +21,20 @@ func FromTokens(tokens []token.Token) (*Expression, error) {
 	// Create a root node and use it as our current expression.
 	current := New()
 
-	// Next current is used for when we want to change the current node
-	// after we receive the next operand.
-	var nextCurrent *Expression
-
 	// Last operand is saved for when we encounter a function call.
 	// We assume that the last operand was the function name.
 	// It is also used for the function call detection itself.
 	// We set this variable back to nil when we see an operator.
 	var lastOperand *Expression
 
+	// An operator that appears where an operand is expected
+	// is a prefix operator like the dereference in `*p`.
+	expectOperand := true
+
 	// We iterate over all tokens and adjust the expression tree as we go.
 	for i, t := range tokens {
 		switch t.Kind {
-		case token.GroupStart:
+		case token.GroupStart, token.ArrayStart, token.BlockStart:
 			if groupLevel == 0 {
 				groupPosition = i + 1
 			}
@@ -42,6 +42,52 @@ func FromTokens(tokens []token.Token) (*Expression, error) {
 			groupLevel++
 			continue
 
+		case token.ArrayEnd:
+			groupLevel--
+
+			if groupLevel == 0 {
+				// Array element access
+				if lastOperand == nil || groupPosition == i {
+					return nil, errors.New(errors.InvalidExpression)
+				}
+
+				index, err := FromTokens(tokens[groupPosition:i])
+
+				if err != nil {
+					return nil, err
+				}
+
+				arrayStart := tokens[groupPosition-1]
+				current = addOperation(current, token.Token{Kind: token.Operator, Position: arrayStart.Position, Bytes: arrayStart.Bytes})
+				current.AddChild(index)
+				lastOperand = nil
+				expectOperand = false
+			}
+
+			continue
+
+		case token.BlockEnd:
+			groupLevel--
+
+			if groupLevel == 0 {
+				// Struct literals like `Point{x: 1, y: 2}`
+				if lastOperand == nil || lastOperand.IsFunctionCall || lastOperand.Token.Kind != token.Identifier {
+					return nil, errors.New(errors.InvalidExpression)
+				}
+
+				lastOperand.IsFunctionCall = true
+				lastOperand.IsStructLiteral = true
+				fields, err := multiExpressionList(withoutNewLines(tokens[groupPosition:i]))
+
+				if err != nil {
+					return nil, err
+				}
+
+				lastOperand.Children = fields
+			}
+
+			continue
+
 		case token.GroupEnd:
 			groupLevel--
 
@@ -67,11 +113,7 @@ func FromTokens(tokens []token.Token) (*Expression, error) {
 
 				lastOperand = operand
 				current.AddChild(operand)
-
-				if nextCurrent != nil {
-					current = nextCurrent
-					nextCurrent = nil
-				}
+				expectOperand = false
 			}
 
 			continue
@@ -83,64 +125,22 @@ func FromTokens(tokens []token.Token) (*Expression, error) {
 		}
 
 		switch t.Kind {
-		case token.Identifier, token.Number, token.Text:
+		case token.Identifier, token.Number, token.Text, token.Keyword:
 			operand := FromToken(t)
 			lastOperand = operand
 			current.AddChild(operand)
-
-			if nextCurrent != nil {
-				current = nextCurrent
-				nextCurrent = nil
-			}
+			expectOperand = false
 
 		case token.Operator:
 			lastOperand = nil
 
-			if current.Token.Kind != token.Operator {
-				current.Token = t
+			if operators.All[t.Text()].Kind == operators.Unary || (expectOperand && operators.IsPrefix(t.Text())) {
+				current = addUnaryOperation(current, t)
 				continue
 			}
 
-			// Compare operator priority
-			oldOperator := current.Token.Text()
-			oldOperatorPriority := operators.All[oldOperator].Priority
-			newOperator := t.Text()
-			newOperatorPriority := operators.All[newOperator].Priority
-
-			if newOperatorPriority > oldOperatorPriority {
-				// Let's say we have the expression (1 + 2 * 3)
-				// At first, we encountered 1 + 2 and generated this tree:
-				//   + (current)
-				//  / \
-				// 1   2
-
-				// Now we encountered a higher priority operator.
-				// We need to take the last operand and replace it with the higher priority operation:
-				//   +
-				//  / \
-				// 1   * (current)
-				//    /
-				//   2
-
-				// Take the last child
-				lastChild := current.LastChild()
-
-				// Create a new expression for the higher priority operation
-				newOperation := New()
-				newOperation.Token = t
-				newOperation.AddChild(lastChild)
-				newOperation.SetParent(current)
-
-				// The new operator becomes the current expression until we added the second operand entirely.
-				nextCurrent = current
-				current = newOperation
-				continue
-			}
-
-			newOperation := New()
-			newOperation.Token = t
-			current.SetParent(newOperation)
-			current = newOperation
+			current = addOperation(current, t)
+			expectOperand = true
 		}
 	}
 
@@ -162,6 +162,66 @@ func FromTokens(tokens []token.Token) (*Expression, error) {
 	return current, nil
 }
 
+// addOperation inserts a new operation into the tree
+// and returns the expression that receives the next operand.
+func addOperation(current *Expression, t token.Token) *Expression {
+	if current.Token.Kind != token.Operator {
+		current.Token = t
+		return current
+	}
+
+	// Walk up the tree until we find an operation with a lower priority.
+	// Operators with the same priority are evaluated from left to right.
+	newOperatorPriority := operators.All[t.Text()].Priority
+	parent := current
+
+	for parent.Parent != nil && newOperatorPriority <= operators.All[parent.Token.Text()].Priority {
+		parent = parent.Parent
+	}
+
+	newOperation := New()
+	newOperation.Token = t
+
+	if newOperatorPriority > operators.All[parent.Token.Text()].Priority {
+		// Let's say we have the expression (1 + 2 * 3)
+		// At first, we encountered 1 + 2 and generated this tree:
+		//   + (parent)
+		//  / \
+		// 1   2
+
+		// Now we encountered a higher priority operator.
+		// We need to take the last operand and replace it with the higher priority operation:
+		//   +
+		//  / \
+		// 1   * (current)
+		//    /
+		//   2
+		lastChild := parent.LastChild()
+		newOperation.AddChild(lastChild)
+		newOperation.SetParent(parent)
+		return newOperation
+	}
+
+	// The new operation has the lowest priority
+	// and therefore becomes the new root.
+	parent.SetParent(newOperation)
+	return newOperation
+}
+
+// addUnaryOperation inserts an operation with a single operand into the tree
+// and returns the expression that receives the operand.
+func addUnaryOperation(current *Expression, t token.Token) *Expression {
+	if current.Token.Kind != token.Operator && len(current.Children) == 0 {
+		current.Token = t
+		return current
+	}
+
+	newOperation := New()
+	newOperation.Token = t
+	newOperation.SetParent(current)
+	return newOperation
+}
+
 // FromToken generates an expression for a single token.
 func FromToken(t token.Token) *Expression {
 	operand := New()
@@ -229,3 +289,33 @@ func multiExpressionList(tokens []token.Token) ([]*Expression, error) {
 
 	return list, nil
 }
+
+// withoutNewLines removes the newlines and comments between the fields of a multi-line struct literal.
+// The tokens are only copied when they contain a newline or a comment.
+func withoutNewLines(tokens []token.Token) []token.Token {
+	var filtered []token.Token
+
+	for i, t := range tokens {
+		isNewLine := t.Kind == token.NewLine || t.Kind == token.Comment
+
+		if filtered == nil {
+			if !isNewLine {
+				continue
+			}
+
+			filtered = make([]token.Token, i, len(tokens))
+			copy(filtered, tokens[:i])
+			continue
+		}
+
+		if !isNewLine {
+			filtered = append(filtered, t)
+		}
+	}
+
+	if filtered == nil {
+		return tokens
+	}
+
+	return filtered
+}
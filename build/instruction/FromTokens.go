@@ -3,6 +3,8 @@ package instruction
 import (
 	"fmt"
 
+	"github.com/akyoto/q/build/errors"
+	"github.com/akyoto/q/build/operators"
 	"github.com/akyoto/q/build/token"
 )
 
@@ -12,11 +14,17 @@ func FromTokens(tokens []token.Token) ([]Instruction, *Error) {
 	start := 0
 	instruction := Instruction{}
 	groups := 0
+	literals := 0
 	blocks := []Kind{}
 
 	for i, t := range tokens {
 		switch t.Kind {
 		case token.NewLine:
+			// Struct literals can span multiple lines.
+			if literals > 0 {
+				continue
+			}
+
 			if start == i {
 				start = i + 1
 				continue
@@ -27,7 +35,11 @@ func FromTokens(tokens []token.Token) ([]Instruction, *Error) {
 				instruction.Kind = Invalid
 				start = i + 1
 
-			case Return, Expect, Ensure, Assignment, Invalid:
+			case Return, Expect, Ensure, Break, Continue, Assignment, Invalid:
+				if isMatchBlock(blocks) {
+					return nil, &Error{errors.InstructionOutsideCase.Error(), start, false}
+				}
+
 				instruction.Tokens = tokens[start:i]
 				instruction.Position = start
 				instructions = append(instructions, instruction)
@@ -41,7 +53,7 @@ func FromTokens(tokens []token.Token) ([]Instruction, *Error) {
 				continue
 			}
 
-			if t.Text() != "=" {
+			if operators.All[t.Text()].Kind != operators.Assignment {
 				continue
 			}
 
@@ -75,6 +87,10 @@ func FromTokens(tokens []token.Token) ([]Instruction, *Error) {
 				continue
 			}
 
+			if isMatchBlock(blocks) {
+				return nil, &Error{errors.InstructionOutsideCase.Error(), start, false}
+			}
+
 			instruction.Tokens = tokens[start : i+1]
 			instruction.Position = start
 			instructions = append(instructions, instruction)
@@ -105,6 +121,11 @@ func FromTokens(tokens []token.Token) ([]Instruction, *Error) {
 		// 	}
 
 		case token.Keyword:
+			if instruction.Kind == ElseStart && t.Text() == "if" && tokens[i-1].Text() == "else" {
+				instruction.Kind = ElseIfStart
+				continue
+			}
+
 			if instruction.Kind != Invalid {
 				continue
 			}
@@ -116,6 +137,12 @@ func FromTokens(tokens []token.Token) ([]Instruction, *Error) {
 				instruction.Kind = Assignment
 			case "if":
 				instruction.Kind = IfStart
+			case "else":
+				if !endsBranch(instructions) {
+					return nil, &Error{errors.ElseWithoutIf.Error(), i, false}
+				}
+
+				instruction.Kind = ElseStart
 			case "for":
 				instruction.Kind = ForStart
 			case "struct":
@@ -124,17 +151,37 @@ func FromTokens(tokens []token.Token) ([]Instruction, *Error) {
 				instruction.Kind = Return
 			case "loop":
 				instruction.Kind = LoopStart
+			case "match":
+				instruction.Kind = MatchStart
 			case "expect":
 				instruction.Kind = Expect
 			case "ensure":
 				instruction.Kind = Ensure
+			case "break":
+				instruction.Kind = Break
+			case "continue":
+				instruction.Kind = Continue
 			default:
 				return nil, &Error{"Keyword not implemented", i, false}
 			}
 
 		case token.BlockStart:
+			if isStructLiteral(tokens, i, instruction.Kind, groups, literals) {
+				literals++
+				continue
+			}
+
+			// Every block directly inside of a match block is a case.
+			if isMatchBlock(blocks) {
+				if instruction.Kind != Invalid {
+					return nil, &Error{errors.InstructionOutsideCase.Error(), start, false}
+				}
+
+				instruction.Kind = CaseStart
+			}
+
 			switch instruction.Kind {
-			case IfStart, ForStart, LoopStart:
+			case IfStart, ElseIfStart, ElseStart, ForStart, LoopStart, MatchStart, CaseStart:
 				// OK.
 
 			default:
@@ -151,18 +198,35 @@ func FromTokens(tokens []token.Token) ([]Instruction, *Error) {
 			start = i + 1
 
 		case token.BlockEnd:
+			if literals > 0 {
+				literals--
+				continue
+			}
+
 			block := blocks[len(blocks)-1]
 
 			switch block {
 			case IfStart:
 				instruction.Kind = IfEnd
 
+			case ElseIfStart:
+				instruction.Kind = ElseIfEnd
+
+			case ElseStart:
+				instruction.Kind = ElseEnd
+
 			case ForStart:
 				instruction.Kind = ForEnd
 
 			case LoopStart:
 				instruction.Kind = LoopEnd
 
+			case MatchStart:
+				instruction.Kind = MatchEnd
+
+			case CaseStart:
+				instruction.Kind = CaseEnd
+
 			case StructStart:
 				instruction.Kind = StructEnd
 
@@ -180,6 +244,10 @@ func FromTokens(tokens []token.Token) ([]Instruction, *Error) {
 			blocks = blocks[:len(blocks)-1]
 
 		case token.Comment:
+			if literals > 0 {
+				continue
+			}
+
 			instruction.Kind = Comment
 		}
 	}
@@ -192,3 +260,30 @@ func FromTokens(tokens []token.Token) ([]Instruction, *Error) {
 
 	return instructions, nil
 }
+
+// endsBranch returns true if the last instruction ends
+// an if block that can be continued by an else branch.
+func endsBranch(instructions []Instruction) bool {
+	if len(instructions) == 0 {
+		return false
+	}
+
+	last := instructions[len(instructions)-1].Kind
+	return last == IfEnd || last == ElseIfEnd
+}
+
+// isMatchBlock returns true if the innermost block is a match block.
+func isMatchBlock(blocks []Kind) bool {
+	return len(blocks) > 0 && blocks[len(blocks)-1] == MatchStart
+}
+
+// isStructLiteral returns true if the block start at the given index begins a struct literal like `Point{x: 1}`.
+// Struct literals follow the type name and can only appear in assignments, return values and call parameters,
+// the bracket after the condition of an if or a loop always starts a block.
+func isStructLiteral(tokens []token.Token, index int, kind Kind, groups int, literals int) bool {
+	if index == 0 || tokens[index-1].Kind != token.Identifier {
+		return false
+	}
+
+	return literals > 0 || groups > 0 || kind == Assignment || kind == Return
+}
@@ -25,6 +25,26 @@ func TestInstructionsFromTokens(t *testing.T) {
 			{instruction.Assignment, nil, 6},
 			{instruction.IfEnd, nil, 10},
 		}},
+		{[]byte("if x > 1 {\nx = 2\n} else {\nx = 3\n}\n"), []instruction.Instruction{
+			{instruction.IfStart, nil, 0},
+			{instruction.Assignment, nil, 6},
+			{instruction.IfEnd, nil, 10},
+			{instruction.ElseStart, nil, 11},
+			{instruction.Assignment, nil, 14},
+			{instruction.ElseEnd, nil, 18},
+		}},
+		{[]byte("if x > 1 {\nx = 2\n} else if x > 0 {\nx = 3\n}\n"), []instruction.Instruction{
+			{instruction.IfStart, nil, 0},
+			{instruction.Assignment, nil, 6},
+			{instruction.IfEnd, nil, 10},
+			{instruction.ElseIfStart, nil, 11},
+			{instruction.Assignment, nil, 18},
+			{instruction.ElseIfEnd, nil, 22},
+		}},
+		{[]byte("x += 1\ny <<= x\n"), []instruction.Instruction{
+			{instruction.Assignment, nil, 0},
+			{instruction.Assignment, nil, 4},
+		}},
 		{[]byte("for i = 0..2 {}\n"), []instruction.Instruction{
 			{instruction.ForStart, nil, 0},
 			{instruction.ForEnd, nil, 7},
@@ -34,6 +54,29 @@ func TestInstructionsFromTokens(t *testing.T) {
 			{instruction.Call, nil, 7},
 			{instruction.ForEnd, nil, 10},
 		}},
+		{[]byte("loop x < 3 && y > 0 {\nx = 1\n}\n"), []instruction.Instruction{
+			{instruction.LoopStart, nil, 0},
+			{instruction.Assignment, nil, 10},
+			{instruction.LoopEnd, nil, 14},
+		}},
+		{[]byte("match x {\nColor.Red {\nf()\n}\n_ {}\n}\n"), []instruction.Instruction{
+			{instruction.MatchStart, nil, 0},
+			{instruction.CaseStart, nil, 4},
+			{instruction.Call, nil, 9},
+			{instruction.CaseEnd, nil, 13},
+			{instruction.CaseStart, nil, 15},
+			{instruction.CaseEnd, nil, 17},
+			{instruction.MatchEnd, nil, 19},
+		}},
+		{[]byte("p = Point{\nx: 1,\n}\nf(Point{})\n"), []instruction.Instruction{
+			{instruction.Assignment, nil, 0},
+			{instruction.Call, nil, 12},
+		}},
+		{[]byte("loop {\nbreak\n}\n"), []instruction.Instruction{
+			{instruction.LoopStart, nil, 0},
+			{instruction.Break, nil, 3},
+			{instruction.LoopEnd, nil, 5},
+		}},
 	}
 
 	for _, pattern := range usagePatterns {
@@ -19,6 +19,18 @@ const (
 	// IfEnd represents the end of the branch.
 	IfEnd
 
+	// ElseIfStart represents the start of a chained branch.
+	ElseIfStart
+
+	// ElseIfEnd represents the end of a chained branch.
+	ElseIfEnd
+
+	// ElseStart represents the start of the alternative branch.
+	ElseStart
+
+	// ElseEnd represents the end of the alternative branch.
+	ElseEnd
+
 	// ForStart represents the start of the for loop.
 	ForStart
 
@@ -31,6 +43,18 @@ const (
 	// LoopEnd represents the end of the infinite loop.
 	LoopEnd
 
+	// MatchStart represents the start of the match statement.
+	MatchStart
+
+	// MatchEnd represents the end of the match statement.
+	MatchEnd
+
+	// CaseStart represents the start of a case inside of a match statement.
+	CaseStart
+
+	// CaseEnd represents the end of a case inside of a match statement.
+	CaseEnd
+
 	// StructStart represents the start of the struct.
 	StructStart
 
@@ -46,6 +70,12 @@ const (
 	// Ensure represents the ensure statement.
 	Ensure
 
+	// Break represents the break statement.
+	Break
+
+	// Continue represents the continue statement.
+	Continue
+
 	// Comment represents a comment.
 	Comment
 )
@@ -65,6 +95,18 @@ func (kind Kind) String() string {
 	case IfEnd:
 		return "IfEnd"
 
+	case ElseIfStart:
+		return "ElseIfStart"
+
+	case ElseIfEnd:
+		return "ElseIfEnd"
+
+	case ElseStart:
+		return "ElseStart"
+
+	case ElseEnd:
+		return "ElseEnd"
+
 	case ForStart:
 		return "ForStart"
 
@@ -77,6 +119,18 @@ func (kind Kind) String() string {
 	case LoopEnd:
 		return "LoopEnd"
 
+	case MatchStart:
+		return "MatchStart"
+
+	case MatchEnd:
+		return "MatchEnd"
+
+	case CaseStart:
+		return "CaseStart"
+
+	case CaseEnd:
+		return "CaseEnd"
+
 	case StructStart:
 		return "StructStart"
 
@@ -89,6 +143,12 @@ func (kind Kind) String() string {
 	case Ensure:
 		return "Ensure"
 
+	case Break:
+		return "Break"
+
+	case Continue:
+		return "Continue"
+
 	case Invalid:
 		return "Invalid"
 
@@ -2,14 +2,22 @@ package keywords
 
 // All defines the keywords used in the language.
 var All = map[string]bool{
-	"ensure": true,
-	"expect": true,
-	"for":    true,
-	"if":     true,
-	"import": true,
-	"let":    true,
-	"loop":   true,
-	"mut":    true,
-	"return": true,
-	"struct": true,
+	"break":    true,
+	"const":    true,
+	"continue": true,
+	"else":     true,
+	"enum":     true,
+	"ensure":   true,
+	"expect":   true,
+	"false":    true,
+	"for":      true,
+	"if":       true,
+	"import":   true,
+	"let":      true,
+	"loop":     true,
+	"match":    true,
+	"mut":      true,
+	"return":   true,
+	"struct":   true,
+	"true":     true,
 }
@@ -6,24 +6,32 @@ var All = map[string]*Operator{
 	// Parameters
 	// ",": {",", 1, Default, true},
 
+	// Struct literal fields
+	":": {":", 1, Default, true},
+
 	// Assignment
-	"=":   {"=", 2, Assignment, true},
-	"+=":  {"+=", 2, Assignment, true},
-	"-=":  {"-=", 2, Assignment, true},
-	"*=":  {"*=", 2, Assignment, true},
-	"/=":  {"/=", 2, Assignment, true},
-	">>=": {">>=", 2, Assignment, true},
-	"<<=": {"<<=", 2, Assignment, true},
+	"=":    {"=", 2, Assignment, true},
+	"+=":   {"+=", 2, Assignment, true},
+	"-=":   {"-=", 2, Assignment, true},
+	"*=":   {"*=", 2, Assignment, true},
+	"/=":   {"/=", 2, Assignment, true},
+	"%=":   {"%=", 2, Assignment, true},
+	"&=":   {"&=", 2, Assignment, true},
+	"|=":   {"|=", 2, Assignment, true},
+	"^=":   {"^=", 2, Assignment, true},
+	">>=":  {">>=", 2, Assignment, true},
+	">>>=": {">>>=", 2, Assignment, true},
+	"<<=":  {"<<=", 2, Assignment, true},
 
 	// Send and receive
 	"->": {"->", 3, Default, true},
 	"<-": {"->", 3, Default, true},
 
 	// Logical OR
-	"||": {"||", 4, Default, true},
+	"||": {"||", 4, Logical, true},
 
 	// Logical AND
-	"&&": {"&&", 5, Default, true},
+	"&&": {"&&", 5, Logical, true},
 
 	// Comparison
 	"==": {"==", 6, Comparison, false},
@@ -34,14 +42,30 @@ var All = map[string]*Operator{
 	"<": {"<", 7, Comparison, true},
 	">": {">", 7, Comparison, true},
 
-	// Arithmetic operations
+	// Arithmetic and bitwise operations
 	"+": {"+", 8, Default, false},
-	"-": {"-", 8, Default, false},
+	"-": {"-", 8, Default, true},
+	"|": {"|", 8, Default, false},
+	"^": {"^", 8, Default, false},
+
+	"*":   {"*", 9, Default, false},
+	"/":   {"/", 9, Default, true},
+	"%":   {"%", 9, Default, true},
+	"&":   {"&", 9, Default, false},
+	"<<":  {"<<", 9, Default, true},
+	">>":  {">>", 9, Default, true},
+	">>>": {">>>", 9, Default, true},
 
-	"*": {"*", 9, Default, false},
-	"/": {"/", 9, Default, true},
-	"%": {"%", 9, Default, true},
+	// Logical NOT
+	"!": {"!", 10, Unary, true},
+
+	// Package, field and array element access
+	".": {".", 11, Default, true},
+	"[": {"[", 11, Default, true},
+}
 
-	// Package and field access
-	".": {".", 10, Default, true},
+// IsPrefix returns true if the operator can also be written
+// in front of a single operand like the dereference `*p` and the address `&p.x`.
+func IsPrefix(symbol string) bool {
+	return symbol == "*" || symbol == "&"
 }
@@ -12,6 +12,12 @@ const (
 
 	// Call represents a function call.
 	Comparison
+
+	// Logical combines the results of comparisons.
+	Logical
+
+	// Unary operators have a single operand on their right side.
+	Unary
 )
 
 // String returns the text representation.
@@ -23,6 +29,12 @@ func (kind Kind) String() string {
 	case Comparison:
 		return "Comparison"
 
+	case Logical:
+		return "Logical"
+
+	case Unary:
+		return "Unary"
+
 	case Default:
 		return "Default"
 
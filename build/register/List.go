@@ -38,3 +38,14 @@ func (registers List) ByName(name string) *Register {
 
 	return nil
 }
+
+// Contains returns true if the register is part of the list.
+func (registers List) Contains(register *Register) bool {
+	for _, other := range registers {
+		if other == register {
+			return true
+		}
+	}
+
+	return false
+}
@@ -2,11 +2,16 @@ package register
 
 // Manager manages the allocation state of registers.
 type Manager struct {
-	All         List
-	General     List
-	Call        List
-	Syscall     List
-	ReturnValue List
+	All              List
+	General          List
+	Call             List
+	Syscall          List
+	ReturnValue      List
+	Float            List
+	FloatCall        List
+	FloatReturnValue List
+	Stack            *Register
+	Frame            *Register
 }
 
 // NewManager creates a new register manager.
@@ -29,6 +34,23 @@ func NewManager() *Manager {
 		{ID: 12, Name: "r13"},
 		{ID: 13, Name: "r14"},
 		{ID: 14, Name: "r15"},
+		{ID: 15, Name: "rsp"},
+		{ID: 16, Name: "xmm0", float: true},
+		{ID: 17, Name: "xmm1", float: true},
+		{ID: 18, Name: "xmm2", float: true},
+		{ID: 19, Name: "xmm3", float: true},
+		{ID: 20, Name: "xmm4", float: true},
+		{ID: 21, Name: "xmm5", float: true},
+		{ID: 22, Name: "xmm6", float: true},
+		{ID: 23, Name: "xmm7", float: true},
+		{ID: 24, Name: "xmm8", float: true},
+		{ID: 25, Name: "xmm9", float: true},
+		{ID: 26, Name: "xmm10", float: true},
+		{ID: 27, Name: "xmm11", float: true},
+		{ID: 28, Name: "xmm12", float: true},
+		{ID: 29, Name: "xmm13", float: true},
+		{ID: 30, Name: "xmm14", float: true},
+		{ID: 31, Name: "xmm15", float: true},
 	}
 
 	// To simplify the lists below,
@@ -48,6 +70,23 @@ func NewManager() *Manager {
 	r13 := &registers[12]
 	r14 := &registers[13]
 	r15 := &registers[14]
+	rsp := &registers[15]
+	xmm0 := &registers[16]
+	xmm1 := &registers[17]
+	xmm2 := &registers[18]
+	xmm3 := &registers[19]
+	xmm4 := &registers[20]
+	xmm5 := &registers[21]
+	xmm6 := &registers[22]
+	xmm7 := &registers[23]
+	xmm8 := &registers[24]
+	xmm9 := &registers[25]
+	xmm10 := &registers[26]
+	xmm11 := &registers[27]
+	xmm12 := &registers[28]
+	xmm13 := &registers[29]
+	xmm14 := &registers[30]
+	xmm15 := &registers[31]
 
 	// Register configuration
 	manager := &Manager{
@@ -67,10 +106,26 @@ func NewManager() *Manager {
 			r13,
 			r14,
 			r15,
+			rsp,
+			xmm0,
+			xmm1,
+			xmm2,
+			xmm3,
+			xmm4,
+			xmm5,
+			xmm6,
+			xmm7,
+			xmm8,
+			xmm9,
+			xmm10,
+			xmm11,
+			xmm12,
+			xmm13,
+			xmm14,
+			xmm15,
 		},
 		General: List{
 			rbx,
-			rbp,
 			r12,
 			r13,
 			r14,
@@ -98,12 +153,86 @@ func NewManager() *Manager {
 			rcx,
 			r11,
 		},
+		Float: List{
+			xmm8,
+			xmm9,
+			xmm10,
+			xmm11,
+			xmm12,
+			xmm13,
+			xmm14,
+			xmm15,
+		},
+		FloatCall: List{
+			xmm0,
+			xmm1,
+			xmm2,
+			xmm3,
+			xmm4,
+			xmm5,
+			xmm6,
+			xmm7,
+		},
+		FloatReturnValue: List{
+			xmm0,
+			xmm1,
+		},
+		Stack: rsp,
+		Frame: rbp,
 	}
 
 	return manager
 }
 
+// ParameterRegisters returns the registers for parameters following the System V calling convention.
+// Integers and floating-point numbers are assigned to the next free register of their own list.
+// Parameters that don't fit into the registers are passed on the stack and receive a nil register.
+func (manager *Manager) ParameterRegisters(isFloat []bool) List {
+	return distribute(isFloat, manager.Call, manager.FloatCall)
+}
+
+// ReturnValueRegisters returns the registers for return values.
+// Like parameters, floating-point numbers are returned in their own registers.
+// Return values that don't fit into the registers receive a nil register.
+func (manager *Manager) ReturnValueRegisters(isFloat []bool) List {
+	return distribute(isFloat, manager.ReturnValue, manager.FloatReturnValue)
+}
+
+// ListFor returns the general purpose registers of the same class as the given register.
+func (manager *Manager) ListFor(register *Register) List {
+	if register.IsFloat() {
+		return manager.Float
+	}
+
+	return manager.General
+}
+
 // ByID returns the register with the given ID.
 func (manager *Manager) ByID(id ID) *Register {
 	return manager.All[id]
 }
+
+// distribute assigns the next free register of the integer or the floating-point list to each value.
+func distribute(isFloat []bool, integers List, floats List) List {
+	registers := make(List, len(isFloat))
+	nextInteger := 0
+	nextFloat := 0
+
+	for i, float := range isFloat {
+		if float {
+			if nextFloat < len(floats) {
+				registers[i] = floats[nextFloat]
+				nextFloat++
+			}
+
+			continue
+		}
+
+		if nextInteger < len(integers) {
+			registers[i] = integers[nextInteger]
+			nextInteger++
+		}
+	}
+
+	return registers
+}
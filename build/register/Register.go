@@ -11,6 +11,7 @@ import (
 type Register struct {
 	ID     ID
 	Name   string
+	float  bool
 	usedBy fmt.Stringer
 }
 
@@ -58,6 +59,11 @@ func (register *Register) IsFree() bool {
 	return register.usedBy == nil
 }
 
+// IsFloat returns true if the register holds floating-point values.
+func (register *Register) IsFloat() bool {
+	return register.float
+}
+
 // String returns a human-readable representation of the register.
 func (register *Register) String() string {
 	return register.StringWithUser(register.UserString())
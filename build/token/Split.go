@@ -0,0 +1,28 @@
+package token
+
+// Split splits the tokens at every token of the given kind that is not inside of a group.
+func Split(tokens []Token, kind Kind) [][]Token {
+	var parts [][]Token
+	groupLevel := 0
+	start := 0
+
+	for i, token := range tokens {
+		switch token.Kind {
+		case GroupStart, ArrayStart, BlockStart:
+			groupLevel++
+
+		case GroupEnd, ArrayEnd, BlockEnd:
+			groupLevel--
+
+		case kind:
+			if groupLevel != 0 {
+				continue
+			}
+
+			parts = append(parts, tokens[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(parts, tokens[start:])
+}
@@ -65,6 +65,20 @@ func Tokenize(buffer []byte, tokens []Token) ([]Token, uint16) {
 		// Numbers
 		case (c >= '0' && c <= '9') || (c == '-' && lastTokenKind != Number && lastTokenKind != Identifier && lastTokenKind != GroupEnd && lastTokenKind != ArrayEnd && buffer[i+1] >= '0' && buffer[i+1] <= '9'):
 			processedBytes = i
+			isFloat := false
+			digitStart := i
+
+			if c == '-' {
+				digitStart++
+			}
+
+			// Hexadecimal, binary and octal numbers start with 0x, 0b or 0o.
+			// Their digits are checked when the number is parsed.
+			hasBasePrefix := buffer[digitStart] == '0' && digitStart+1 < uint16(len(buffer)) && isBasePrefix(buffer[digitStart+1])
+
+			if hasBasePrefix {
+				i = digitStart + 1
+			}
 
 			for {
 				i++
@@ -75,6 +89,21 @@ func Tokenize(buffer []byte, tokens []Token) ([]Token, uint16) {
 
 				c = buffer[i]
 
+				// A single dot followed by a digit makes it a floating-point number
+				if c == '.' && !isFloat && !hasBasePrefix && i+1 < uint16(len(buffer)) && buffer[i+1] >= '0' && buffer[i+1] <= '9' {
+					isFloat = true
+					continue
+				}
+
+				// Digits can be separated by underscores like 1_000_000
+				if c == '_' {
+					continue
+				}
+
+				if hasBasePrefix && ((c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')) {
+					continue
+				}
+
 				if c < '0' || c > '9' {
 					i--
 					break
@@ -105,7 +134,7 @@ func Tokenize(buffer []byte, tokens []Token) ([]Token, uint16) {
 			token = Token{Comment, processedBytes, trimmed}
 
 		// Operators
-		case c == '=' || c == ':' || c == '+' || c == '-' || c == '*' || c == '/' || c == '<' || c == '>' || c == '!':
+		case c == '=' || c == ':' || c == '+' || c == '-' || c == '*' || c == '/' || c == '<' || c == '>' || c == '!' || c == '&' || c == '|' || c == '%' || c == '^':
 			processedBytes = i
 
 			for {
@@ -117,12 +146,18 @@ func Tokenize(buffer []byte, tokens []Token) ([]Token, uint16) {
 
 				c = buffer[i]
 
-				if !(c == '=' || c == ':' || c == '+' || c == '-' || c == '*' || c == '/' || c == '<' || c == '>' || c == '!') {
+				if !(c == '=' || c == ':' || c == '+' || c == '-' || c == '*' || c == '/' || c == '<' || c == '>' || c == '!' || c == '&' || c == '|' || c == '%' || c == '^') {
 					i--
 					break
 				}
 			}
 
+			// Operators can follow each other without a space, e.g. `a && !b`,
+			// therefore the longest known operator is used.
+			for i > processedBytes && operators.All[string(buffer[processedBytes:i+1])] == nil {
+				i--
+			}
+
 			token = Token{Operator, processedBytes, buffer[processedBytes : i+1]}
 
 			if operators.All[string(token.Bytes)] == nil {
@@ -150,10 +185,31 @@ func Tokenize(buffer []byte, tokens []Token) ([]Token, uint16) {
 						text = append(text, '\n')
 					case 'r':
 						text = append(text, '\r')
+					case 't':
+						text = append(text, '\t')
+					case '0':
+						text = append(text, 0)
 					case '\\':
 						text = append(text, '\\')
 					case '"':
 						text = append(text, '"')
+					case 'x':
+						// Hexadecimal escapes like \x41 consist of exactly 2 digits
+						if i+2 >= uint16(len(buffer)) {
+							return tokens, processedBytes
+						}
+
+						high, highValid := hexDigit(buffer[i+1])
+						low, lowValid := hexDigit(buffer[i+2])
+
+						if !highValid || !lowValid {
+							return tokens, processedBytes
+						}
+
+						text = append(text, high<<4|low)
+						i += 2
+					default:
+						return tokens, processedBytes
 					}
 
 					escape = false
@@ -193,6 +249,13 @@ func Tokenize(buffer []byte, tokens []Token) ([]Token, uint16) {
 		// Array start
 		case c == '[':
 			token = Token{ArrayStart, i, arrayStartBytes}
+			end := arrayTypeEnd(buffer, i)
+
+			// Array types like [16]Int are a single identifier
+			if end != 0 {
+				token = Token{Identifier, i, buffer[i:end]}
+				i = end - 1
+			}
 
 		// Array end
 		case c == ']':
@@ -233,3 +296,55 @@ func Tokenize(buffer []byte, tokens []Token) ([]Token, uint16) {
 
 	return tokens, processedBytes
 }
+
+// hexDigit returns the value of a hexadecimal digit.
+func hexDigit(c byte) (byte, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0', true
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10, true
+	case c >= 'A' && c <= 'F':
+		return c - 'A' + 10, true
+	default:
+		return 0, false
+	}
+}
+
+// isBasePrefix returns true if the character follows the 0 of a hexadecimal, binary or octal number.
+func isBasePrefix(c byte) bool {
+	return c == 'x' || c == 'b' || c == 'o'
+}
+
+// arrayTypeEnd returns the end position of an array type like [16]Int
+// starting at the given position or zero if there is no array type.
+func arrayTypeEnd(buffer []byte, start uint16) uint16 {
+	i := start + 1
+
+	for i < uint16(len(buffer)) && buffer[i] >= '0' && buffer[i] <= '9' {
+		i++
+	}
+
+	if i == start+1 || i+1 >= uint16(len(buffer)) || buffer[i] != ']' {
+		return 0
+	}
+
+	i++
+	c := buffer[i]
+
+	if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && c != '_' {
+		return 0
+	}
+
+	for i < uint16(len(buffer)) {
+		c = buffer[i]
+
+		if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && (c < '0' || c > '9') && c != '_' {
+			break
+		}
+
+		i++
+	}
+
+	return i
+}
@@ -47,6 +47,66 @@ func TestTokenize(t *testing.T) {
 			{token.BlockEnd, 29, []byte{'}'}},
 			{token.NewLine, 30, []byte{'\n'}},
 		}},
+		{[]byte("a && (b || c)\n"), []token.Token{
+			{token.Identifier, 0, []byte("a")},
+			{token.Operator, 2, []byte("&&")},
+			{token.GroupStart, 5, []byte{'('}},
+			{token.Identifier, 6, []byte("b")},
+			{token.Operator, 8, []byte("||")},
+			{token.Identifier, 11, []byte("c")},
+			{token.GroupEnd, 12, []byte{')'}},
+			{token.NewLine, 13, []byte{'\n'}},
+		}},
+		{[]byte("a[i] = [16]Int()\n"), []token.Token{
+			{token.Identifier, 0, []byte("a")},
+			{token.ArrayStart, 1, []byte{'['}},
+			{token.Identifier, 2, []byte("i")},
+			{token.ArrayEnd, 3, []byte{']'}},
+			{token.Operator, 5, []byte("=")},
+			{token.Identifier, 7, []byte("[16]Int")},
+			{token.GroupStart, 14, []byte{'('}},
+			{token.GroupEnd, 15, []byte{')'}},
+			{token.NewLine, 16, []byte{'\n'}},
+		}},
+		{[]byte("x = -1.5 * p.y\n"), []token.Token{
+			{token.Identifier, 0, []byte("x")},
+			{token.Operator, 2, []byte("=")},
+			{token.Number, 4, []byte("-1.5")},
+			{token.Operator, 9, []byte("*")},
+			{token.Identifier, 11, []byte("p")},
+			{token.Operator, 12, []byte(".")},
+			{token.Identifier, 13, []byte("y")},
+			{token.NewLine, 14, []byte{'\n'}},
+		}},
+		{[]byte("f(0x1F, -0b1010, 0o644, 1_000_000)\n"), []token.Token{
+			{token.Identifier, 0, []byte("f")},
+			{token.GroupStart, 1, []byte{'('}},
+			{token.Number, 2, []byte("0x1F")},
+			{token.Separator, 6, []byte{','}},
+			{token.Number, 8, []byte("-0b1010")},
+			{token.Separator, 15, []byte{','}},
+			{token.Number, 17, []byte("0o644")},
+			{token.Separator, 22, []byte{','}},
+			{token.Number, 24, []byte("1_000_000")},
+			{token.GroupEnd, 33, []byte{')'}},
+			{token.NewLine, 34, []byte{'\n'}},
+		}},
+		{[]byte("ok = true&&!done\n"), []token.Token{
+			{token.Identifier, 0, []byte("ok")},
+			{token.Operator, 3, []byte("=")},
+			{token.Keyword, 5, []byte("true")},
+			{token.Operator, 9, []byte("&&")},
+			{token.Operator, 11, []byte("!")},
+			{token.Identifier, 12, []byte("done")},
+			{token.NewLine, 16, []byte{'\n'}},
+		}},
+		{[]byte("print(\"a\\t\\\"b\\\"\\x41\\n\")\n"), []token.Token{
+			{token.Identifier, 0, []byte("print")},
+			{token.GroupStart, 5, []byte{'('}},
+			{token.Text, 7, []byte("a\t\"b\"A\n")},
+			{token.GroupEnd, 22, []byte{')'}},
+			{token.NewLine, 23, []byte{'\n'}},
+		}},
 		{[]byte("# A comment.\n"), []token.Token{
 			{token.Comment, 0, []byte("A comment.")},
 			{token.NewLine, 12, []byte{'\n'}},
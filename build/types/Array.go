@@ -0,0 +1,42 @@
+package types
+
+import (
+	"fmt"
+	"sync"
+)
+
+var (
+	arrays      = map[arrayKey]*Type{}
+	arraysMutex sync.Mutex
+)
+
+// arrayKey identifies an array type.
+type arrayKey struct {
+	element *Type
+	length  uint
+}
+
+// Array returns the fixed-size array type for the given element type and length.
+// Array types are created only once so that they can be compared like all other types.
+func Array(element *Type, length uint) *Type {
+	key := arrayKey{element, length}
+
+	arraysMutex.Lock()
+	defer arraysMutex.Unlock()
+
+	typ, exists := arrays[key]
+
+	if exists {
+		return typ
+	}
+
+	typ = &Type{
+		Name:    fmt.Sprintf("[%d]%s", length, element.Name),
+		Size:    element.Size * length,
+		Element: element,
+		Length:  length,
+	}
+
+	arrays[key] = typ
+	return typ
+}
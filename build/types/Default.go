@@ -2,12 +2,18 @@ package types
 
 // Default represents the default types in our type system.
 var Default = map[string]*Type{
+	"Bool":    Bool,
 	"Byte":    Byte,
 	"Int":     Int,
 	"Int64":   Int64,
 	"Int32":   Int32,
 	"Int16":   Int16,
 	"Int8":    Int8,
+	"UInt":    UInt,
+	"UInt64":  UInt64,
+	"UInt32":  UInt32,
+	"UInt16":  UInt16,
+	"UInt8":   UInt8,
 	"Float":   Float,
 	"Float64": Float64,
 	"Float32": Float32,
@@ -1,3 +1,32 @@
 package types
 
+import "sync"
+
 var Pointer = Int
+
+var (
+	pointers      = map[*Type]*Type{}
+	pointersMutex sync.Mutex
+)
+
+// PointerTo returns the typed pointer type for the given element type.
+// Pointer types are created only once so that they can be compared like all other types.
+func PointerTo(element *Type) *Type {
+	pointersMutex.Lock()
+	defer pointersMutex.Unlock()
+
+	typ, exists := pointers[element]
+
+	if exists {
+		return typ
+	}
+
+	typ = &Type{
+		Name:    "*" + element.Name,
+		Size:    8,
+		Element: element,
+	}
+
+	pointers[element] = typ
+	return typ
+}
@@ -1,3 +1,9 @@
 package types
 
-var Text = Pointer
+// Text points to the first byte of a text.
+// The length is stored in the 8 bytes in front of the first byte
+// and a zero byte follows the last byte for system calls expecting C strings.
+var Text = &Type{Name: "Text", Size: 8}
+
+// TextLengthSize is the number of bytes used to store the length of a text.
+const TextLengthSize = 8
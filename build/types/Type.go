@@ -2,9 +2,14 @@ package types
 
 // Type represents a type in the type system.
 type Type struct {
-	Name   string
-	Size   uint
-	Fields []*Field
+	Name    string
+	Size    uint
+	Align   uint
+	Packed  bool
+	Fields  []*Field
+	Element *Type
+	Length  uint
+	Cases   []string
 }
 
 // FieldByName returns the field with the given name.
@@ -18,6 +23,72 @@ func (typ *Type) FieldByName(name string) *Field {
 	return nil
 }
 
+// CaseIndex returns the value of the enum case with the given name or -1 if it doesn't exist.
+func (typ *Type) CaseIndex(name string) int {
+	for index, caseName := range typ.Cases {
+		if caseName == name {
+			return index
+		}
+	}
+
+	return -1
+}
+
+// Alignment returns the number of bytes that the address of a value needs to be a multiple of.
+// Structs are aligned like their largest field and arrays like their elements,
+// every other type is aligned to its own size.
+func (typ *Type) Alignment() uint {
+	switch {
+	case typ.Align != 0:
+		return typ.Align
+
+	case typ.IsArray():
+		return typ.Element.Alignment()
+
+	case typ.Size == 0:
+		return 1
+
+	default:
+		return typ.Size
+	}
+}
+
+// IsArray returns true if the type is a fixed-size array.
+func (typ *Type) IsArray() bool {
+	return typ.Element != nil && typ.Length != 0
+}
+
+// IsPointer returns true if the type is a typed pointer.
+func (typ *Type) IsPointer() bool {
+	return typ.Element != nil && typ.Length == 0
+}
+
+// IsEnum returns true if the type is an enumeration of named cases.
+func (typ *Type) IsEnum() bool {
+	return len(typ.Cases) > 0
+}
+
+// IsFloat returns true if the type is a floating-point number.
+func (typ *Type) IsFloat() bool {
+	return typ == Float64 || typ == Float32
+}
+
+// IsInteger returns true if the type is a signed or unsigned integer number.
+func (typ *Type) IsInteger() bool {
+	return typ == Int64 || typ == Int32 || typ == Int16 || typ == Int8 || typ.IsUnsigned()
+}
+
+// IsUnsigned returns true if the type is an unsigned integer number.
+func (typ *Type) IsUnsigned() bool {
+	return typ == UInt64 || typ == UInt32 || typ == UInt16 || typ == UInt8
+}
+
+// IsAssignableTo returns true if values of the type can be passed as the expected type.
+// Texts can be passed as a pointer to their first byte.
+func (typ *Type) IsAssignableTo(expected *Type) bool {
+	return typ == expected || (typ == Text && expected == Pointer)
+}
+
 // String returns the type name.
 func (typ *Type) String() string {
 	if typ == nil {
@@ -0,0 +1,9 @@
+package types
+
+var (
+	UInt64 = &Type{Name: "UInt64", Size: 8}
+	UInt32 = &Type{Name: "UInt32", Size: 4}
+	UInt16 = &Type{Name: "UInt16", Size: 2}
+	UInt8  = &Type{Name: "UInt8", Size: 1}
+	UInt   = UInt64
+)
@@ -9,6 +9,7 @@ import (
 func Help() {
 	log.Error.Println("")
 	log.Error.Println("q build", log.Faint.Sprint("[directory]"))
+	log.Error.Println("q layout", log.Faint.Sprint("[directory] [struct]"))
 	log.Error.Println("q system")
 	log.Error.Println("")
 	log.Error.Println(color.YellowString("# build"))
@@ -20,6 +21,10 @@ func Help() {
 	log.Error.Println("-v --verbose  Enables all optional information.")
 	log.Error.Println("-O --optimize Optimizes for performance.")
 	log.Error.Println("")
+	log.Error.Println(color.YellowString("# layout"))
+	log.Error.Println("")
+	log.Error.Println("Shows the offsets, sizes and padding of the struct fields.")
+	log.Error.Println("")
 	log.Error.Println(color.YellowString("# system"))
 	log.Error.Println("")
 	log.Error.Println("Displays information about the system.")
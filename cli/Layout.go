@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/akyoto/color"
+	"github.com/akyoto/q/build"
+	"github.com/akyoto/q/build/log"
+	"github.com/akyoto/q/build/types"
+)
+
+// Layout shows the offsets, sizes and the padding of the struct fields in the directory.
+// Without a type name, the layout of every struct in the main package is shown.
+// It returns the exit code of the command.
+func Layout(arguments []string) int {
+	directory := "."
+	typeName := ""
+
+	switch len(arguments) {
+	case 0:
+	case 1:
+		directory = arguments[0]
+	case 2:
+		directory = arguments[0]
+		typeName = arguments[1]
+	default:
+		Help()
+		return 2
+	}
+
+	stat, err := os.Stat(directory)
+
+	if err != nil {
+		log.Error.Println(err)
+		return 1
+	}
+
+	if !stat.IsDir() {
+		log.Error.Println("Build path must be a directory")
+		return 2
+	}
+
+	b, err := build.New(directory)
+
+	if err != nil {
+		log.Error.Println(err)
+		return 1
+	}
+
+	err = b.ResolveTypes()
+
+	if err != nil {
+		log.Error.Println(err)
+		return 1
+	}
+
+	var structs []*types.Type
+
+	for name, structure := range b.Environment.Structs {
+		if structure.Type.IsEnum() {
+			continue
+		}
+
+		if name == typeName || (typeName == "" && !strings.Contains(name, ".")) {
+			structs = append(structs, structure.Type)
+		}
+	}
+
+	if typeName != "" && len(structs) == 0 {
+		log.Error.Printf("Unknown struct '%s'\n", typeName)
+		return 1
+	}
+
+	sort.Slice(structs, func(a, b int) bool {
+		return structs[a].Name < structs[b].Name
+	})
+
+	for index, typ := range structs {
+		if index > 0 {
+			log.Info.Println()
+		}
+
+		showLayout(typ)
+	}
+
+	return 0
+}
+
+// showLayout shows the fields of a single struct in the order they are stored in memory.
+func showLayout(typ *types.Type) {
+	const padding = "padding"
+	key := log.Faint.Sprint
+	nameWidth := len(padding)
+	typeWidth := len("type")
+
+	for _, field := range typ.Fields {
+		if len(field.Name) > nameWidth {
+			nameWidth = len(field.Name)
+		}
+
+		if len(field.Type.Name) > typeWidth {
+			typeWidth = len(field.Type.Name)
+		}
+	}
+
+	attributes := ""
+
+	if typ.Packed {
+		attributes = ", packed"
+	}
+
+	log.Info.Printf("%s "+key("(size %d, align %d%s)")+"\n", color.YellowString(typ.Name), typ.Size, typ.Alignment(), attributes)
+	log.Info.Printf(key("%6s  %-*s  %-*s  %4s")+"\n", "offset", nameWidth, "field", typeWidth, "type", "size")
+	offset := uint(0)
+
+	for _, field := range typ.Fields {
+		if field.Offset > offset {
+			log.Info.Printf(key("%6d  %-*s  %-*s  %4d")+"\n", offset, nameWidth, padding, typeWidth, "", field.Offset-offset)
+		}
+
+		log.Info.Printf("%6d  %-*s  %-*s  %4d\n", field.Offset, nameWidth, field.Name, typeWidth, field.Type.Name, field.Type.Size)
+		offset = field.Offset + field.Type.Size
+	}
+
+	if typ.Size > offset {
+		log.Info.Printf(key("%6d  %-*s  %-*s  %4d")+"\n", offset, nameWidth, padding, typeWidth, "", typ.Size-offset)
+	}
+}
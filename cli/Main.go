@@ -35,6 +35,10 @@ func Main() int {
 		return 0
 	}
 
+	if command == "layout" {
+		return Layout(os.Args[2:])
+	}
+
 	if command != "build" {
 		Help()
 		return 2
@@ -20,6 +20,10 @@ func TestCLI(t *testing.T) {
 		{[]string{"q", "system"}, 0},
 		{[]string{"q", "build", "non-existing-directory"}, 1},
 		{[]string{"q", "build", "examples/hello/hello.q"}, 2},
+		{[]string{"q", "layout", "examples/layout"}, 0},
+		{[]string{"q", "layout", "examples/layout", "Header"}, 0},
+		{[]string{"q", "layout", "examples/layout", "Unknown"}, 1},
+		{[]string{"q", "layout", "non-existing-directory"}, 1},
 	}
 
 	for _, example := range examples {
@@ -14,35 +14,76 @@ func TestErrors(t *testing.T) {
 		File          string
 		ExpectedError error
 	}{
+		{"ambiguous-call.q", &errors.AmbiguousCall{Name: "f", Candidates: []string{"f(Int64, Text)", "f(Text, Int64)"}}},
+		{"break-outside-loop.q", errors.BreakOutsideLoop},
+		{"constant-already-exists.q", &errors.ConstantAlreadyExists{Name: "a"}},
+		{"constant-assignment.q", errors.ConstantAssignment},
+		{"continue-outside-loop.q", errors.ContinueOutsideLoop},
+		{"duplicate-case.q", &errors.DuplicateCase{Name: "Color.Red"}},
+		{"duplicate-field.q", &errors.DuplicateField{Name: "x"}},
+		{"else-without-if.q", errors.ElseWithoutIf},
 		{"ensure-no-return-type.q", errors.EnsureWithoutFunctionType},
 		{"for-missing-upper-limit.q", errors.MissingRangeLimit},
+		{"function-already-exists.q", &errors.FunctionAlreadyExists{Name: "f(Int64)"}},
 		{"for-missing-range.q", errors.MissingRange},
 		{"for-missing-start-value.q", errors.MissingRangeStart},
+		{"immutable-parameter.q", &errors.ImmutableVariable{Name: "p"}},
+		{"immutable-receiver.q", errors.ImmutableReceiver},
 		{"immutable-variable.q", &errors.ImmutableVariable{Name: "a"}},
+		{"index-out-of-bounds.q", &errors.IndexOutOfBounds{Index: 4, Length: 4}},
 		{"import-already-exists.q", &errors.ImportNameAlreadyExists{Name: "sys", ImportPath: "sys"}},
 		{"ineffective-assignment.q", &errors.IneffectiveAssignment{Name: "a"}},
+		{"ineffective-assignment-else.q", &errors.IneffectiveAssignment{Name: "a"}},
+		{"instruction-outside-case.q", errors.InstructionOutsideCase},
+		{"invalid-byte-count.q", &errors.InvalidByteCount{ByteCount: "3"}},
+		{"invalid-type-bool.q", &errors.InvalidType{Name: "Int64", Expected: "Bool"}},
 		{"invalid-type-field-assign.q", &errors.InvalidType{Name: "Int64", Expected: "Int32"}},
+		{"invalid-type-float.q", &errors.InvalidType{Name: "Int64", Expected: "Float64"}},
+		{"invalid-type-width.q", &errors.InvalidType{Name: "Int16", Expected: "Int8"}},
+		{"missing-case.q", &errors.MissingCase{Name: "Color.Blue"}},
+		{"missing-field-name.q", errors.MissingFieldName},
 		{"missing-opening-bracket.q", &errors.MissingCharacter{Character: "("}},
 		{"missing-closing-bracket.q", &errors.MissingCharacter{Character: ")"}},
 		{"missing-return-type.q", errors.MissingReturnType},
 		{"missing-return-value.q", &errors.MissingReturnValue{ReturnType: "Int64"}},
 		{"missing-struct-name.q", errors.MissingStructName},
 		{"missing-type.q", &errors.MissingType{Of: "length"}},
+		{"mutable-function.q", errors.MutableFunction},
+		{"not-a-pointer.q", errors.NotAPointer},
+		{"not-addressable.q", errors.NotAddressable},
+		{"not-a-number.q", &errors.NotANumber{Expression: "0x1_0000_0000_0000_0000"}},
+		{"not-an-array.q", errors.NotAnArray},
+		{"number-out-of-range.q", &errors.NumberOutOfRange{Number: "-1", Type: "UInt8"}},
 		{"package-doesnt-exist.q", &errors.PackageDoesntExist{ImportPath: "non.existing.package"}},
 		{"parameter-count.q", &errors.ParameterCount{FunctionName: "sum", CountGiven: 1, CountRequired: 2}},
+		{"result-count.q", &errors.ResultCount{FunctionName: "f", CountGiven: 3, CountReturned: 2}},
+		{"return-count.q", &errors.ReturnCount{FunctionName: "f", CountGiven: 1, CountRequired: 2}},
 		{"return-without-type.q", errors.ReturnWithoutFunctionType},
+		{"struct-assignment.q", errors.StructAssignment},
+		{"struct-cycle.q", errors.StructCycle},
+		{"text-assignment.q", errors.TextAssignment},
 		{"unnecessary-newlines.q", errors.UnnecessaryNewlines},
+		{"unreachable-case.q", errors.UnreachableCase},
+		{"unused-constant.q", &errors.UnusedConstant{Name: "a"}},
 		{"unused-variable.q", &errors.UnusedVariable{Name: "a"}},
+		{"unused-variable-else.q", &errors.UnusedVariable{Name: "b"}},
 		{"unused-mutable.q", &errors.UnmodifiedMutable{Name: "a"}},
+		{"unused-mutable-parameter.q", &errors.UnmodifiedMutable{Name: "p"}},
+		{"unknown-case.q", &errors.UnknownCase{Name: "Gren", CorrectName: "Green", TypeName: "Color"}},
 		{"unknown-field.q", &errors.UnknownField{Name: "z", TypeName: "Point"}},
+		{"unknown-field-read.q", &errors.UnknownField{Name: "z", TypeName: "Point"}},
 		{"unknown-field-suggestion.q", &errors.UnknownField{Name: "xx", CorrectName: "x", TypeName: "Point"}},
+		{"unknown-method.q", &errors.UnknownMethod{TypeName: "Point", Name: "lenght"}},
 		{"unknown-function.q", &errors.UnknownFunction{Name: "z"}},
 		{"unknown-function-suggestion.q", &errors.UnknownFunction{Name: "prin", CorrectName: "print"}},
 		{"unknown-expression.q", &errors.UnknownExpression{Expression: "\")"}},
+		{"unknown-overload.q", &errors.UnknownOverload{Name: "f", Arguments: "Point, Int64", CorrectName: "f(Int64, Int64)"}},
 		{"unknown-variable.q", &errors.UnknownVariable{Name: "a"}},
 		{"unknown-variable-suggestion.q", &errors.UnknownVariable{Name: "lengt", CorrectName: "length"}},
+		{"unknown-variable-interpolation.q", &errors.UnknownVariable{Name: "lengt", CorrectName: "length"}},
 		{"unknown-package.q", &errors.UnknownPackage{Name: "sy", CorrectName: "sys"}},
 		{"unused-parameter.q", &errors.UnusedVariable{Name: "b"}},
+		{"unsupported-operator.q", &errors.UnsupportedOperator{Operator: "%", Type: "Float64"}},
 		{"variable-already-exists.q", &errors.VariableAlreadyExists{Name: "a"}},
 	}
 
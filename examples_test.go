@@ -12,13 +12,35 @@ var examples = []struct {
 	ExpectedExitCode int
 }{
 	{"hello", "Hello\n", 0},
-	{"contracts", "f: expect [n < 10]\n", 1},
+	{"allocation", "0\n10\n4\n", 41},
+	{"array", "", 84},
+	{"bits", "", 76},
+	{"bool", "", 14},
+	{"bounds", "main: index out of bounds numbers[i]\n", 1},
+	{"branches", "zero\none\ntwo\nmany\nedge\nin\nin\nout\nedge\nin\n", 0},
+	{"constants", "26 / 32\n66\n", 47},
+	{"enum", "red\ngreen\nblue\nnorth\nblue\n", 65},
+	{"divmod", "", 101},
+	{"contracts", "f: expect [n > 0 && n < 10]\n", 1},
 	{"fibonacci", "", 89},
 	{"files", "", 0},
-	{"functions", "123456789\n123456789\n123456789\n123456789\n", 0},
-	{"loops", "Hello\nHello\nHello\n\nH\nHe\nHel\nHell\nHello\n", 0},
+	{"float", "", 95},
+	{"functions", "10\n10\n10\n10\n", 105},
+	{"integers", "", 3},
+	{"layout", "20\n300\n20\n300\n", 42},
+	{"literals", "255\n165\n493\n1000000\n-16\n0\n", 5},
+	{"loops", "Hello\nHello\nHello\n\nH\nHe\nHel\nHell\nHello\nHe\nHel\nLoop\nLoop\nWhile\nWhile\nWhile\n0\n5\n10\n", 0},
 	{"memory", "ABCD\n", 0},
-	{"struct", "", 20},
+	{"methods", "7\n13, 24\n37\n", 61},
+	{"overload", "", 76},
+	{"parameters", "", 32},
+	{"pointers", "", 33},
+	{"rectangle", "2, 3\n10 x 4\n0, 0\n", 42},
+	{"spill", "", 160},
+	{"struct", "", 36},
+	{"text", "Hello\tWorld\n11\n\"quoted\" \\ ABC\na{b}c\n-21\n-7\n7\ntotal: 14 {28 / 2}\n7 x 8\n", 0},
+	{"thread", "created\n", 0},
+	{"unsigned", "", 63},
 }
 
 func TestExamples(t *testing.T) {